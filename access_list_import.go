@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// parseClientRules parses an allowlist with one IP address or CIDR range per
+// line, optionally prefixed with allow or deny. Lines without a prefix are
+// allowed. Blank lines, comments starting with # and repeated rules are skipped.
+func parseClientRules(content string) ([]AccessListClient, error) {
+	var rules []AccessListClient
+	seen := make(map[AccessListClient]bool)
+	for i, line := range strings.Split(content, "\n") {
+		if comment := strings.Index(line, "#"); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+
+		rule := AccessListClient{Directive: "allow"}
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			rule.Address = fields[0]
+		case 2:
+			rule.Directive = strings.ToLower(fields[0])
+			rule.Address = fields[1]
+			if rule.Directive != "allow" && rule.Directive != "deny" {
+				return nil, fmt.Errorf("line %d: invalid directive %q, expected allow or deny", i+1, fields[0])
+			}
+		default:
+			return nil, fmt.Errorf("line %d: expected an address, optionally prefixed with allow or deny", i+1)
+		}
+		if err := validateClientAddress(rule.Address); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if !seen[rule] {
+			seen[rule] = true
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// appendClientRules adds the rules that are not in the list yet. They go
+// before a final "deny all", which would otherwise shadow them.
+func appendClientRules(existing, rules []AccessListClient) []AccessListClient {
+	present := make(map[AccessListClient]bool, len(existing))
+	for _, rule := range existing {
+		present[rule] = true
+	}
+
+	denyAll := AccessListClient{Address: "all", Directive: "deny"}
+	endsWithDenyAll := len(existing) > 0 && existing[len(existing)-1] == denyAll
+
+	result := append([]AccessListClient{}, existing...)
+	if endsWithDenyAll {
+		result = result[:len(result)-1]
+	}
+	for _, rule := range rules {
+		if !present[rule] {
+			present[rule] = true
+			result = append(result, rule)
+		}
+	}
+	if endsWithDenyAll {
+		result = append(result, denyAll)
+	}
+	return result
+}
+
+// diffClientRules returns the rules only in after and the rules only in before
+func diffClientRules(before, after []AccessListClient) (added, removed []AccessListClient) {
+	inBefore := make(map[AccessListClient]bool, len(before))
+	for _, rule := range before {
+		inBefore[rule] = true
+	}
+	inAfter := make(map[AccessListClient]bool, len(after))
+	for _, rule := range after {
+		inAfter[rule] = true
+		if !inBefore[rule] {
+			added = append(added, rule)
+		}
+	}
+	for _, rule := range before {
+		if !inAfter[rule] {
+			removed = append(removed, rule)
+		}
+	}
+	return added, removed
+}
+
+// sameClientRules reports whether two rule lists are equal, including their order
+func sameClientRules(a, b []AccessListClient) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var accessListImportCmd = &cobra.Command{
+	Use:   "import LIST",
+	Short: "Import the IP rules of an access list from a file",
+	Long: `Read IP rules from a file with one IP address or CIDR range per line,
+optionally prefixed with allow or deny, and apply them to an access list given
+by ID or name. Lines without a prefix are allowed; blank lines and comments
+starting with # are skipped.
+
+By default the rules of the list are replaced by those of the file, in file
+order. With --append, rules not in the list yet are added, before a final
+"deny all" rule if the list has one. The added and removed rules are reported.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		file, _ := cmd.Flags().GetString("file")
+		appendRules, _ := cmd.Flags().GetBool("append")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if file == "" {
+			return fmt.Errorf("file is required")
+		}
+
+		var content []byte
+		var err error
+		if file == "-" {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			content, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read rules file: %w", err)
+		}
+		rules, err := parseClientRules(string(content))
+		if err != nil {
+			return fmt.Errorf("invalid rules file: %w", err)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := resolveAccessList(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		clients := rules
+		if appendRules {
+			clients = appendClientRules(list.Clients, rules)
+		}
+		added, removed := diffClientRules(list.Clients, clients)
+
+		for _, rule := range added {
+			fmt.Fprintf(out, "+ %s %s\n", rule.Directive, rule.Address)
+		}
+		for _, rule := range removed {
+			fmt.Fprintf(out, "- %s %s\n", rule.Directive, rule.Address)
+		}
+		summary := fmt.Sprintf("%d added, %d removed, %d rules in total", len(added), len(removed), len(clients))
+
+		if sameClientRules(list.Clients, clients) {
+			fmt.Fprintf(out, "Access list %d (%s) unchanged\n", list.ID, list.Name)
+			return nil
+		}
+		if dryRun {
+			fmt.Fprintf(out, "Dry run, access list %d (%s) would be updated: %s\n", list.ID, list.Name, summary)
+			return nil
+		}
+
+		list.Clients = clients
+		if _, err := client.UpdateAccessList(list.ID, *list); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Updated access list %d (%s): %s\n", list.ID, list.Name, summary)
+		return nil
+	},
+}
+
+func init() {
+	accessListImportCmd.Flags().StringP("file", "f", "", "File with one rule per line, - for stdin")
+	accessListImportCmd.Flags().Bool("append", false, "Add the rules to the list instead of replacing its rules")
+	accessListImportCmd.Flags().Bool("dry-run", false, "Only show the changes")
+
+	accessListCmd.AddCommand(accessListImportCmd)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// accessListRequest is the body NPM expects when updating an access list.
+// Items and clients are always sent, as NPM replaces both with what it
+// receives; users sent without a password keep their current one.
+type accessListRequest struct {
+	Name       string             `json:"name"`
+	SatisfyAny bool               `json:"satisfy_any"`
+	PassAuth   bool               `json:"pass_auth"`
+	Items      []AccessListItem   `json:"items"`
+	Clients    []AccessListClient `json:"clients"`
+}
+
+// UpdateAccessList updates an existing access list
+func (c *APIClient) UpdateAccessList(id int, list AccessList) (*AccessList, error) {
+	request := accessListRequest{
+		Name:       list.Name,
+		SatisfyAny: list.SatisfyAny,
+		PassAuth:   list.PassAuth,
+		Items:      []AccessListItem{},
+		Clients:    append([]AccessListClient{}, list.Clients...),
+	}
+	for _, item := range list.Items {
+		request.Items = append(request.Items, AccessListItem{Username: item.Username, Password: item.Password})
+	}
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal access list: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/nginx/access-lists/%d", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update access list, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var updatedList AccessList
+	if err := decodeJSON(resp.Body, &updatedList); err != nil {
+		return nil, fmt.Errorf("failed to decode updated access list: %w", err)
+	}
+
+	return &updatedList, nil
+}
+
+// validateClientAddress checks an address of an access list rule: an IP
+// address, a CIDR range or "all"
+func validateClientAddress(address string) error {
+	if address == "all" || net.ParseIP(address) != nil {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(address); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid address %q, expected an IP address, a CIDR range or all", address)
+}
+
+// clientRulesValue collects --allow and --deny into one list, so the rules
+// keep the order they were given in, which nginx evaluates them in
+type clientRulesValue struct {
+	directive string
+	rules     *[]AccessListClient
+}
+
+func (v clientRulesValue) String() string {
+	var addresses []string
+	for _, rule := range *v.rules {
+		if rule.Directive == v.directive {
+			addresses = append(addresses, rule.Address)
+		}
+	}
+	return strings.Join(addresses, ",")
+}
+
+func (v clientRulesValue) Set(address string) error {
+	address = strings.TrimSpace(address)
+	if err := validateClientAddress(address); err != nil {
+		return err
+	}
+	*v.rules = append(*v.rules, AccessListClient{Address: address, Directive: v.directive})
+	return nil
+}
+
+func (v clientRulesValue) Type() string {
+	return "address"
+}
+
+// addAccessListRuleFlags registers --allow, --deny, --satisfy-any and
+// --pass-auth, with the rules collected into rules
+func addAccessListRuleFlags(cmd *cobra.Command, rules *[]AccessListClient) {
+	cmd.Flags().Var(clientRulesValue{"allow", rules}, "allow", "Allow an IP address, CIDR range or all (repeatable, kept in order with --deny)")
+	cmd.Flags().Var(clientRulesValue{"deny", rules}, "deny", "Deny an IP address, CIDR range or all (repeatable, kept in order with --allow)")
+	cmd.Flags().Bool("satisfy-any", false, "Grant access when either an IP rule or basic auth matches, instead of both")
+	cmd.Flags().Bool("pass-auth", false, "Pass the Authorization header on to the backend")
+}
+
+// accessListUpdateRules collects the rules of access-list update
+var accessListUpdateRules []AccessListClient
+
+var accessListUpdateCmd = &cobra.Command{
+	Use:   "update LIST",
+	Short: "Update the settings and IP rules of an access list",
+	Long: `Update an access list given by ID or name. Settings not given as flags are
+kept. --allow and --deny replace all IP rules of the list, in the order they
+are given; --clear-rules removes them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		flags := cmd.Flags()
+		clearRules, _ := flags.GetBool("clear-rules")
+		if clearRules && len(accessListUpdateRules) > 0 {
+			return fmt.Errorf("--clear-rules cannot be combined with --allow or --deny")
+		}
+		if flags.Changed("name") {
+			if name, _ := flags.GetString("name"); strings.TrimSpace(name) == "" {
+				return fmt.Errorf("name must not be empty")
+			}
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := resolveAccessList(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if flags.Changed("name") {
+			list.Name, _ = flags.GetString("name")
+		}
+		if flags.Changed("satisfy-any") {
+			list.SatisfyAny, _ = flags.GetBool("satisfy-any")
+		}
+		if flags.Changed("pass-auth") {
+			list.PassAuth, _ = flags.GetBool("pass-auth")
+		}
+		if len(accessListUpdateRules) > 0 || clearRules {
+			list.Clients = accessListUpdateRules
+		}
+
+		updatedList, err := client.UpdateAccessList(list.ID, *list)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully updated access list with ID: %d\n", updatedList.ID)
+		fmt.Fprintf(out, "Name: %s\n", updatedList.Name)
+		fmt.Fprintf(out, "Satisfy: %s\n", satisfyMode(*updatedList))
+		fmt.Fprintf(out, "Pass Auth: %t\n", updatedList.PassAuth)
+		fmt.Fprintf(out, "Clients: %d\n", len(updatedList.Clients))
+		for _, c := range updatedList.Clients {
+			fmt.Fprintf(out, "  %s %s\n", c.Directive, c.Address)
+		}
+		return nil
+	},
+}
+
+func init() {
+	accessListUpdateCmd.Flags().String("name", "", "New name of the access list")
+	addAccessListRuleFlags(accessListUpdateCmd, &accessListUpdateRules)
+	accessListUpdateCmd.Flags().Bool("clear-rules", false, "Remove all IP rules")
+
+	accessListCmd.AddCommand(accessListUpdateCmd)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// readNewPassword reads the password of a new user, from the first line of
+// stdin with passwordStdin, otherwise from a prompt with confirmation
+func readNewPassword(passwordStdin bool) (string, error) {
+	var password string
+	if passwordStdin {
+		line, err := stdinReader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	} else {
+		var err error
+		if password, err = readPassword("Password: "); err != nil {
+			return "", err
+		}
+		confirmation, err := readPassword("Confirm password: ")
+		if err != nil {
+			return "", err
+		}
+		if password != confirmation {
+			return "", fmt.Errorf("passwords do not match")
+		}
+	}
+
+	if password == "" {
+		return "", fmt.Errorf("password must not be empty")
+	}
+	return password, nil
+}
+
+// findAccessListUser returns the index of a basic auth user, or -1
+func findAccessListUser(list AccessList, username string) int {
+	for i, item := range list.Items {
+		if item.Username == username {
+			return i
+		}
+	}
+	return -1
+}
+
+var accessListAddUserCmd = &cobra.Command{
+	Use:   "add-user LIST",
+	Short: "Add a basic auth user to an access list or change its password",
+	Long: `Add a basic auth user to an access list given by ID or name. If the user
+already exists, its password is changed. The passwords of the other users are
+kept.
+
+The user is given with --user, not --username, as the global -u/--username
+flag is the login of the CLI itself.
+
+The password is prompted for, or read from the first line of stdin with
+--password-stdin.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		user, _ := cmd.Flags().GetString("user")
+		passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+		if user == "" {
+			return fmt.Errorf("user is required")
+		}
+		password, err := readNewPassword(passwordStdin)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := resolveAccessList(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		action := "Added"
+		if i := findAccessListUser(*list, user); i >= 0 {
+			list.Items[i].Password = password
+			action = "Changed the password of"
+		} else {
+			list.Items = append(list.Items, AccessListItem{Username: user, Password: password})
+		}
+
+		if _, err := client.UpdateAccessList(list.ID, *list); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "%s user %s in access list %d (%s)\n", action, user, list.ID, list.Name)
+		return nil
+	},
+}
+
+var accessListRemoveUserCmd = &cobra.Command{
+	Use:   "remove-user LIST",
+	Short: "Remove a basic auth user from an access list",
+	Long: `Remove a basic auth user from an access list given by ID or name.
+
+The user is given with --user, not --username, as the global -u/--username
+flag is the login of the CLI itself.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		user, _ := cmd.Flags().GetString("user")
+		if user == "" {
+			return fmt.Errorf("user is required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := resolveAccessList(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		i := findAccessListUser(*list, user)
+		if i < 0 {
+			return fmt.Errorf("access list %d (%s) has no user %s", list.ID, list.Name, user)
+		}
+		list.Items = append(list.Items[:i], list.Items[i+1:]...)
+
+		if _, err := client.UpdateAccessList(list.ID, *list); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Removed user %s from access list %d (%s)\n", user, list.ID, list.Name)
+		return nil
+	},
+}
+
+func init() {
+	accessListAddUserCmd.Flags().String("user", "", "Name of the basic auth user (not --username, which is the CLI login)")
+	accessListAddUserCmd.Flags().Bool("password-stdin", false, "Read the password from stdin instead of prompting")
+
+	accessListRemoveUserCmd.Flags().String("user", "", "Name of the basic auth user (not --username, which is the CLI login)")
+
+	accessListCmd.AddCommand(accessListAddUserCmd)
+	accessListCmd.AddCommand(accessListRemoveUserCmd)
+}
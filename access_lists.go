@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// AccessList represents an access list that restricts access to proxy hosts
+type AccessList struct {
+	ID         int                `json:"id"`
+	Name       string             `json:"name"`
+	SatisfyAny bool               `json:"satisfy_any"`
+	PassAuth   bool               `json:"pass_auth"`
+	Items      []AccessListItem   `json:"items,omitempty"`
+	Clients    []AccessListClient `json:"clients,omitempty"`
+	CreatedOn  string             `json:"created_on"`
+	ModifiedOn string             `json:"modified_on"`
+}
+
+// AccessListItem is a basic auth user of an access list
+type AccessListItem struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// AccessListClient is an IP address or range rule of an access list
+type AccessListClient struct {
+	Address   string `json:"address"`
+	Directive string `json:"directive"`
+}
+
+// GetAccessList fetches a single access list by ID including its clients and auth items
+func (c *APIClient) GetAccessList(id int) (*AccessList, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/access-lists/%d?expand=clients,items", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("access list %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get access list, status: %d", resp.StatusCode)
+	}
+
+	var list AccessList
+	if err := decodeJSON(resp.Body, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode access list: %w", err)
+	}
+
+	return &list, nil
+}
+
+// ListAccessLists lists all access lists including their clients and auth items
+func (c *APIClient) ListAccessLists() ([]AccessList, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/access-lists?expand=clients,items", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list access lists, status: %d", resp.StatusCode)
+	}
+
+	var lists []AccessList
+	if err := decodeJSON(resp.Body, &lists); err != nil {
+		return nil, fmt.Errorf("failed to decode access lists: %w", err)
+	}
+
+	return lists, nil
+}
+
+// CreateAccessList creates a new access list
+func (c *APIClient) CreateAccessList(list AccessList) (*AccessList, error) {
+	jsonData, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal access list: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", "/nginx/access-lists", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create access list, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var createdList AccessList
+	if err := decodeJSON(resp.Body, &createdList); err != nil {
+		return nil, fmt.Errorf("failed to decode created access list: %w", err)
+	}
+
+	return &createdList, nil
+}
+
+// DeleteAccessList deletes an access list by ID
+func (c *APIClient) DeleteAccessList(id int) error {
+	resp, err := c.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/nginx/access-lists/%d", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("access list %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete access list, status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// resolveAccessList returns the access list given by ID or name, including
+// its clients and auth items
+func resolveAccessList(client *APIClient, value string) (*AccessList, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return client.GetAccessList(id)
+	}
+
+	lists, err := client.ListAccessLists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access lists: %w", err)
+	}
+	return findAccessListByName(lists, value)
+}
+
+// accessListHosts returns the proxy hosts restricted by an access list
+func accessListHosts(hosts []ProxyHost, id int) []ProxyHost {
+	var users []ProxyHost
+	for _, host := range hosts {
+		if host.AccessListID == id {
+			users = append(users, host)
+		}
+	}
+	return users
+}
+
+// satisfyMode describes how the rules and users of an access list combine
+func satisfyMode(list AccessList) string {
+	if list.SatisfyAny {
+		return "any"
+	}
+	return "all"
+}
+
+var accessListCmd = &cobra.Command{
+	Use:   "access-list",
+	Short: "Manage access lists",
+}
+
+var accessListExportClientsCmd = &cobra.Command{
+	Use:   "export-clients",
+	Short: "Export the client rules of an access list as nginx directives or CSV",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		id, _ := cmd.Flags().GetInt("id")
+		format, _ := cmd.Flags().GetString("format")
+		if id == 0 {
+			return fmt.Errorf("id is required")
+		}
+		if format != "nginx" && format != "csv" {
+			return fmt.Errorf("invalid format %q, expected nginx or csv", format)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := client.GetAccessList(id)
+		if err != nil {
+			return fmt.Errorf("failed to get access list: %w", err)
+		}
+
+		if format == "csv" {
+			w := csv.NewWriter(out)
+			w.Write([]string{"directive", "address"})
+			for _, c := range list.Clients {
+				w.Write([]string{c.Directive, c.Address})
+			}
+			w.Flush()
+			return w.Error()
+		}
+
+		// Same as the configuration NPM generates: rules in order, then deny everything else
+		fmt.Fprintf(out, "# Access list %d: %s\n", list.ID, list.Name)
+		for _, c := range list.Clients {
+			fmt.Fprintf(out, "%s %s;\n", c.Directive, c.Address)
+		}
+		if len(list.Clients) > 0 {
+			fmt.Fprintln(out, "deny all;")
+		}
+
+		return nil
+	},
+}
+
+var accessListListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all access lists",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		lists, err := client.ListAccessLists()
+		if err != nil {
+			return fmt.Errorf("failed to list access lists: %w", err)
+		}
+
+		if output == "json" {
+			return writeJSON(append([]AccessList{}, lists...))
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tCLIENTS\tUSERS\tSATISFY\tPASS AUTH")
+		for _, list := range lists {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\t%t\n", list.ID, list.Name, len(list.Clients), len(list.Items), satisfyMode(list), list.PassAuth)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var accessListGetCmd = &cobra.Command{
+	Use:   "get LIST",
+	Short: "Show an access list with its rules and users",
+	Long:  `Show an access list, given by ID or name, with its IP rules and basic auth users.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := resolveAccessList(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(list)
+		}
+
+		fmt.Fprintf(out, "ID: %d\n", list.ID)
+		fmt.Fprintf(out, "Name: %s\n", list.Name)
+		fmt.Fprintf(out, "Satisfy: %s\n", satisfyMode(*list))
+		fmt.Fprintf(out, "Pass Auth: %t\n", list.PassAuth)
+		fmt.Fprintf(out, "Clients: %d\n", len(list.Clients))
+		for _, c := range list.Clients {
+			fmt.Fprintf(out, "  %s %s\n", c.Directive, c.Address)
+		}
+		fmt.Fprintf(out, "Users: %d\n", len(list.Items))
+		for _, item := range list.Items {
+			fmt.Fprintf(out, "  %s\n", item.Username)
+		}
+		fmt.Fprintf(out, "Created: %s\n", list.CreatedOn)
+		fmt.Fprintf(out, "Modified: %s\n", list.ModifiedOn)
+
+		return nil
+	},
+}
+
+// accessListCreateRules collects the rules of access-list create
+var accessListCreateRules []AccessListClient
+
+var accessListCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an access list",
+	Long: `Create an access list. IP rules given with --allow and --deny are kept in the
+order they are given; nginx applies the first rule that matches.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		name, _ := cmd.Flags().GetString("name")
+		satisfyAny, _ := cmd.Flags().GetBool("satisfy-any")
+		passAuth, _ := cmd.Flags().GetBool("pass-auth")
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("name is required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := client.CreateAccessList(AccessList{
+			Name:       name,
+			SatisfyAny: satisfyAny,
+			PassAuth:   passAuth,
+			Clients:    accessListCreateRules,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully created access list with ID: %d\n", list.ID)
+		fmt.Fprintf(out, "Name: %s\n", list.Name)
+		fmt.Fprintf(out, "Clients: %d\n", len(accessListCreateRules))
+		return nil
+	},
+}
+
+var accessListUsageCmd = &cobra.Command{
+	Use:   "usage LIST",
+	Short: "Show the proxy hosts protected by an access list",
+	Long: `List the proxy hosts that use an access list, given by ID or name, to confirm
+which services are gated by it before changing its rules.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := resolveAccessList(client, args[0])
+		if err != nil {
+			return err
+		}
+		hosts, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+		users := accessListHosts(hosts, list.ID)
+
+		if output == "json" {
+			return writeJSON(struct {
+				AccessList *AccessList `json:"access_list"`
+				ProxyHosts []ProxyHost `json:"proxy_hosts"`
+			}{list, append([]ProxyHost{}, users...)})
+		}
+
+		fmt.Fprintf(out, "Access list %d %q\n", list.ID, list.Name)
+		if len(users) == 0 {
+			fmt.Fprintln(out, "Not used by any proxy host")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tDOMAINS\tFORWARD\tENABLED")
+		for _, host := range users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%t\n", host.ID, strings.Join(host.DomainNames, ","), hostTarget(host), host.Enabled)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var accessListDeleteCmd = &cobra.Command{
+	Use:   "delete LIST",
+	Short: "Delete an access list",
+	Long: `Delete an access list, given by ID or name. Access lists still used by proxy
+hosts are not deleted unless --force is given, as NPM would make those hosts
+publicly accessible.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		list, err := resolveAccessList(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+		if users := accessListHosts(hosts, list.ID); len(users) > 0 {
+			var lines []string
+			for _, host := range users {
+				lines = append(lines, fmt.Sprintf("  proxy host %d (%s)", host.ID, firstDomain(host.DomainNames)))
+			}
+			if !force {
+				return fmt.Errorf("access list %d is used by %d proxy hosts, they become public without it; use --force to delete it anyway:\n%s",
+					list.ID, len(users), strings.Join(lines, "\n"))
+			}
+			fmt.Fprintf(os.Stderr, "Warning: access list %d is used by %d proxy hosts, they become public:\n%s\n", list.ID, len(users), strings.Join(lines, "\n"))
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Delete access list %d (%s)?", list.ID, list.Name)) {
+			return fmt.Errorf("aborted")
+		}
+
+		if err := client.DeleteAccessList(list.ID); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully deleted access list with ID: %d\n", list.ID)
+		return nil
+	},
+}
+
+func init() {
+	accessListCreateCmd.Flags().String("name", "", "Name of the access list")
+	addAccessListRuleFlags(accessListCreateCmd, &accessListCreateRules)
+
+	accessListDeleteCmd.Flags().Bool("force", false, "Delete the access list even if proxy hosts use it")
+	accessListDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	accessListCmd.AddCommand(accessListListCmd)
+	accessListCmd.AddCommand(accessListGetCmd)
+	accessListCmd.AddCommand(accessListCreateCmd)
+	accessListCmd.AddCommand(accessListUsageCmd)
+	accessListCmd.AddCommand(accessListDeleteCmd)
+
+	accessListExportClientsCmd.Flags().Int("id", 0, "ID of the access list")
+	accessListExportClientsCmd.Flags().String("format", "nginx", "Output format (nginx or csv)")
+
+	accessListCmd.AddCommand(accessListExportClientsCmd)
+	rootCmd.AddCommand(accessListCmd)
+}
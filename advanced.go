@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// checkBraces catches truncated or mismatched nginx blocks, which would make
+// nginx fail to reload with the whole host configuration
+func checkBraces(config string) error {
+	depth := 0
+	for i, line := range strings.Split(config, "\n") {
+		// Braces in comments don't count
+		line, _, _ = strings.Cut(line, "#")
+		for _, r := range line {
+			switch r {
+			case '{':
+				depth++
+			case '}':
+				if depth--; depth < 0 {
+					return fmt.Errorf("unexpected } on line %d", i+1)
+				}
+			}
+		}
+	}
+	if depth > 0 {
+		return fmt.Errorf("%d unclosed { at the end", depth)
+	}
+	return nil
+}
+
+// addAdvancedConfigFlag registers the --advanced-config-file flag
+func addAdvancedConfigFlag(cmd *cobra.Command) {
+	cmd.Flags().String("advanced-config-file", "", "File with custom nginx configuration for the server block of the proxy host")
+}
+
+// applyAdvancedConfigFile replaces the advanced config of the host with the
+// content of --advanced-config-file when it was given
+func applyAdvancedConfigFile(cmd *cobra.Command, host *ProxyHost) error {
+	path, _ := cmd.Flags().GetString("advanced-config-file")
+	if path == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read advanced config file: %w", err)
+	}
+	if err := checkBraces(string(content)); err != nil {
+		return fmt.Errorf("invalid advanced config in %s: %w", path, err)
+	}
+
+	host.AdvancedConfig = string(content)
+	return nil
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// sslIssue describes an SSL misconfiguration of a proxy host
+type sslIssue struct {
+	Host  ProxyHost
+	Issue string
+}
+
+// auditSSL cross-references every proxy host with the certificate it uses
+func auditSSL(hosts []ProxyHost, certs []Certificate, now time.Time) []sslIssue {
+	certsByID := make(map[int]Certificate, len(certs))
+	for _, cert := range certs {
+		certsByID[cert.ID] = cert
+	}
+
+	var issues []sslIssue
+	for _, host := range hosts {
+		if host.CertificateID == 0 {
+			if host.SslForced {
+				issues = append(issues, sslIssue{host, "SSL forced but no certificate assigned"})
+			}
+			continue
+		}
+
+		cert, ok := certsByID[host.CertificateID]
+		if !ok {
+			issues = append(issues, sslIssue{host, fmt.Sprintf("certificate %d does not exist", host.CertificateID)})
+			continue
+		}
+
+		if expiry, err := cert.Expiry(); err == nil && expiry.Before(now) {
+			issues = append(issues, sslIssue{host, fmt.Sprintf("certificate %d (%s) expired on %s", cert.ID, cert.NiceName, expiry.Format(time.DateOnly))})
+		}
+
+		if uncovered := uncoveredDomains(host.DomainNames, cert.DomainNames); len(uncovered) > 0 {
+			issues = append(issues, sslIssue{host, fmt.Sprintf("certificate %d (%s) does not cover %s", cert.ID, cert.NiceName, strings.Join(uncovered, ", "))})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Host.ID < issues[j].Host.ID
+	})
+
+	return issues
+}
+
+// tlsIssue describes a host of any type that serves traffic without HTTPS
+// or without redirecting to it
+type tlsIssue struct {
+	Type    string
+	ID      int
+	Domains []string
+	Issue   string
+}
+
+// auditCertificates returns the certificates not used by any host and the
+// enabled hosts that have no certificate or don't force SSL
+func auditCertificates(hosts *allHosts, certs []Certificate) ([]Certificate, []tlsIssue) {
+	used := make(map[int]bool)
+	var issues []tlsIssue
+	check := func(hostType string, id int, domains []string, enabled bool, certID int, sslForced bool) {
+		if certID != 0 {
+			used[certID] = true
+		}
+		if !enabled {
+			return
+		}
+		switch {
+		case certID == 0:
+			issues = append(issues, tlsIssue{hostType, id, domains, "no certificate"})
+		case !sslForced:
+			issues = append(issues, tlsIssue{hostType, id, domains, "SSL not forced"})
+		}
+	}
+
+	for _, host := range hosts.ProxyHosts {
+		check("proxy host", host.ID, host.DomainNames, host.Enabled, host.CertificateID, host.SslForced)
+	}
+	for _, host := range hosts.RedirectionHosts {
+		check("redirection host", host.ID, host.DomainNames, host.Enabled, host.CertificateID, host.SslForced)
+	}
+	for _, host := range hosts.DeadHosts {
+		check("404 host", host.ID, host.DomainNames, host.Enabled, host.CertificateID, host.SslForced)
+	}
+
+	var unused []Certificate
+	for _, cert := range certs {
+		if !used[cert.ID] {
+			unused = append(unused, cert)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].ID < unused[j].ID
+	})
+
+	return unused, issues
+}
+
+// domainOwner is a host of any type serving a domain name
+type domainOwner struct {
+	Type   string
+	ID     int
+	Domain string
+}
+
+func (o domainOwner) String() string {
+	return fmt.Sprintf("%s %d", o.Type, o.ID)
+}
+
+// domainConflict describes a domain that is served by more than one host
+type domainConflict struct {
+	Domain string
+	Reason string
+	Owners []domainOwner
+}
+
+// allHosts holds the proxy, redirection and 404 hosts of an NPM instance
+type allHosts struct {
+	ProxyHosts       []ProxyHost
+	RedirectionHosts []RedirectionHost
+	DeadHosts        []DeadHost
+}
+
+// listAllHosts fetches proxy, redirection and 404 hosts concurrently
+func listAllHosts(client *APIClient) (*allHosts, error) {
+	var (
+		wg                                sync.WaitGroup
+		hosts                             allHosts
+		proxyErr, redirectionErr, deadErr error
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		hosts.ProxyHosts, proxyErr = client.ListProxyHosts()
+	}()
+	go func() {
+		defer wg.Done()
+		hosts.RedirectionHosts, redirectionErr = client.ListRedirectionHosts()
+	}()
+	go func() {
+		defer wg.Done()
+		hosts.DeadHosts, deadErr = client.ListDeadHosts()
+	}()
+	wg.Wait()
+
+	if proxyErr != nil {
+		return nil, fmt.Errorf("failed to list proxy hosts: %w", proxyErr)
+	}
+	if redirectionErr != nil {
+		return nil, fmt.Errorf("failed to list redirection hosts: %w", redirectionErr)
+	}
+	if deadErr != nil {
+		return nil, fmt.Errorf("failed to list 404 hosts: %w", deadErr)
+	}
+	return &hosts, nil
+}
+
+// listDomainOwners returns every domain name served by a proxy, redirection or 404 host
+func listDomainOwners(client *APIClient) ([]domainOwner, error) {
+	hosts, err := listAllHosts(client)
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []domainOwner
+	for _, host := range hosts.ProxyHosts {
+		for _, domain := range host.DomainNames {
+			owners = append(owners, domainOwner{"proxy host", host.ID, domain})
+		}
+	}
+	for _, host := range hosts.RedirectionHosts {
+		for _, domain := range host.DomainNames {
+			owners = append(owners, domainOwner{"redirection host", host.ID, domain})
+		}
+	}
+	for _, host := range hosts.DeadHosts {
+		for _, domain := range host.DomainNames {
+			owners = append(owners, domainOwner{"404 host", host.ID, domain})
+		}
+	}
+
+	return owners, nil
+}
+
+// certificateUsers returns the hosts using a certificate, each with its first domain
+func (h *allHosts) certificateUsers(certID int) []domainOwner {
+	var users []domainOwner
+	for _, user := range h.certificateUsage(certID) {
+		users = append(users, domainOwner{user.Type, user.ID, firstDomain(user.Domains)})
+	}
+	return users
+}
+
+// findDomainConflicts reports domains served by more than one host, and
+// wildcard domains of one host that cover a concrete domain of another
+func findDomainConflicts(owners []domainOwner) []domainConflict {
+	sameHost := func(a, b domainOwner) bool {
+		return a.Type == b.Type && a.ID == b.ID
+	}
+
+	byDomain := make(map[string][]domainOwner)
+	for _, owner := range owners {
+		domain := strings.ToLower(owner.Domain)
+		byDomain[domain] = append(byDomain[domain], owner)
+	}
+
+	domains := make([]string, 0, len(byDomain))
+	for domain := range byDomain {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	var conflicts []domainConflict
+	for _, domain := range domains {
+		var distinct []domainOwner
+		for _, owner := range byDomain[domain] {
+			if len(distinct) == 0 || !sameHost(distinct[len(distinct)-1], owner) {
+				distinct = append(distinct, owner)
+			}
+		}
+		if len(distinct) > 1 {
+			conflicts = append(conflicts, domainConflict{domain, "duplicate", distinct})
+		}
+	}
+
+	for _, wildcard := range owners {
+		if !strings.HasPrefix(wildcard.Domain, "*.") {
+			continue
+		}
+		for _, owner := range owners {
+			if sameHost(wildcard, owner) || strings.HasPrefix(owner.Domain, "*.") {
+				continue
+			}
+			if matchesDomain(wildcard.Domain, owner.Domain) {
+				conflicts = append(conflicts, domainConflict{
+					Domain: strings.ToLower(owner.Domain),
+					Reason: "overlaps " + wildcard.Domain,
+					Owners: []domainOwner{owner, wildcard},
+				})
+			}
+		}
+	}
+
+	return conflicts
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check the configuration for common problems",
+}
+
+var auditSSLCmd = &cobra.Command{
+	Use:          "ssl",
+	Short:        "Check that proxy hosts use valid certificates covering all their domains",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		var (
+			wg                 sync.WaitGroup
+			hosts              []ProxyHost
+			certs              []Certificate
+			hostsErr, certsErr error
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			hosts, hostsErr = client.ListProxyHosts()
+		}()
+		go func() {
+			defer wg.Done()
+			certs, certsErr = client.ListCertificates()
+		}()
+		wg.Wait()
+
+		if hostsErr != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", hostsErr)
+		}
+		if certsErr != nil {
+			return fmt.Errorf("failed to list certificates: %w", certsErr)
+		}
+
+		issues := auditSSL(hosts, certs, time.Now())
+		if len(issues) == 0 {
+			fmt.Fprintf(out, "No SSL issues found in %d proxy hosts\n", len(hosts))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tDOMAINS\tISSUE")
+		for _, issue := range issues {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", issue.Host.ID, strings.Join(issue.Host.DomainNames, ","), issue.Issue)
+		}
+		w.Flush()
+
+		return fmt.Errorf("found %d SSL issues", len(issues))
+	},
+}
+
+var auditCertsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Find unused certificates and hosts without HTTPS",
+	Long: `Report certificates that are not used by any host, which are candidates for
+deletion, and enabled proxy, redirection and 404 hosts that serve traffic
+without a certificate or without redirecting HTTP to HTTPS.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		hosts, err := listAllHosts(client)
+		if err != nil {
+			return err
+		}
+		certs, err := client.ListCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+
+		unused, issues := auditCertificates(hosts, certs)
+		if len(unused) == 0 && len(issues) == 0 {
+			fmt.Fprintf(out, "No issues found in %d certificates and %d hosts\n", len(certs),
+				len(hosts.ProxyHosts)+len(hosts.RedirectionHosts)+len(hosts.DeadHosts))
+			return nil
+		}
+
+		if len(unused) > 0 {
+			fmt.Fprintln(out, "Unused certificates (delete with cert delete):")
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tDOMAINS\tEXPIRES")
+			for _, cert := range unused {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", cert.ID, cert.NiceName, strings.Join(cert.DomainNames, ","), cert.ExpiresOn)
+			}
+			w.Flush()
+		}
+
+		if len(issues) > 0 {
+			if len(unused) > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintln(out, "Hosts without HTTPS (fix with cert attach or update --ssl-forced):")
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TYPE\tID\tDOMAINS\tISSUE")
+			for _, issue := range issues {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", issue.Type, issue.ID, strings.Join(issue.Domains, ","), issue.Issue)
+			}
+			w.Flush()
+		}
+
+		return fmt.Errorf("found %d unused certificates and %d hosts without HTTPS", len(unused), len(issues))
+	},
+}
+
+var auditDomainsCmd = &cobra.Command{
+	Use:          "domains",
+	Short:        "Find domains served by more than one host",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		owners, err := listDomainOwners(client)
+		if err != nil {
+			return err
+		}
+
+		conflicts := findDomainConflicts(owners)
+		if len(conflicts) == 0 {
+			fmt.Fprintf(out, "No conflicts found in %d domains\n", len(owners))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DOMAIN\tCONFLICT\tHOSTS")
+		for _, conflict := range conflicts {
+			hosts := make([]string, len(conflict.Owners))
+			for i, owner := range conflict.Owners {
+				hosts[i] = owner.String()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", conflict.Domain, conflict.Reason, strings.Join(hosts, ", "))
+		}
+		w.Flush()
+
+		return fmt.Errorf("found %d domain conflicts", len(conflicts))
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(auditSSLCmd)
+	auditCmd.AddCommand(auditCertsCmd)
+	auditCmd.AddCommand(auditDomainsCmd)
+	rootCmd.AddCommand(auditCmd)
+}
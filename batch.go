@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchResult counts the outcome of the items of a bulk operation
+type BatchResult struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	TimedOut  int `json:"timed_out"`
+}
+
+// Record counts the outcome of one item
+func (r *BatchResult) Record(err error) {
+	switch {
+	case err == nil:
+		r.Succeeded++
+	case errors.Is(err, context.DeadlineExceeded):
+		r.TimedOut++
+	default:
+		r.Failed++
+	}
+}
+
+// String summarizes the result, like "3 succeeded, 1 failed, 1 timed out"
+func (r BatchResult) String() string {
+	return fmt.Sprintf("%d succeeded, %d failed, %d timed out", r.Succeeded, r.Failed, r.TimedOut)
+}
+
+// Err returns an error when any item failed or timed out
+func (r BatchResult) Err() error {
+	if r.Failed == 0 && r.TimedOut == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d items did not complete (%s)", r.Failed+r.TimedOut, r.Succeeded+r.Failed+r.TimedOut, r)
+}
+
+// runConcurrently calls item for the indices 0 to n-1 with at most parallel
+// calls at a time and returns the error of every item by index
+func runConcurrently(n, parallel int, item func(i int) error) []error {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = item(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// runWithTimeout runs one item of a bulk operation, giving all API requests
+// it makes through the client passed to item a shared deadline. A zero timeout
+// means no deadline.
+func (c *APIClient) runWithTimeout(timeout time.Duration, item func(client *APIClient) error) error {
+	if timeout <= 0 {
+		return item(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := item(c.WithContext(ctx))
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, context.DeadlineExceeded)
+	}
+	return err
+}
+
+// WithContext returns a copy of the client whose requests use ctx. The copy
+// shares the token, credentials and cache with the original.
+func (c *APIClient) WithContext(ctx context.Context) *APIClient {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// requestContext returns the context for the next API request
+func (c *APIClient) requestContext() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// bulkChange is the planned update of one proxy host
+type bulkChange struct {
+	Host    ProxyHost
+	Updated ProxyHost
+	Before  map[string]json.RawMessage
+	After   map[string]json.RawMessage
+	Fields  []string
+}
+
+// planBulkChange applies the field assignments to a host and validates the
+// result. Fields are changed only, not set, when the host already has the value.
+func planBulkChange(host ProxyHost, assignments []string) (bulkChange, error) {
+	before, err := editableFields(host)
+	if err != nil {
+		return bulkChange{}, err
+	}
+
+	changes := make(map[string]json.RawMessage, len(assignments))
+	for _, assignment := range assignments {
+		field, value, err := parseFieldAssignment(assignment, before)
+		if err != nil {
+			return bulkChange{}, err
+		}
+		changes[field] = value
+	}
+
+	updated, err := mergeProxyHostFields(host, changes)
+	if err != nil {
+		return bulkChange{}, err
+	}
+	if err := validateProxyHost(updated); err != nil {
+		return bulkChange{}, err
+	}
+
+	after, err := editableFields(updated)
+	if err != nil {
+		return bulkChange{}, err
+	}
+	return bulkChange{host, updated, before, after, changedFields(before, after)}, nil
+}
+
+// selectBulkHosts returns the hosts given by ID, in the given order, or all hosts matching the filters
+func selectBulkHosts(hosts []ProxyHost, ids []int, filters []proxyHostFilter) ([]ProxyHost, error) {
+	if len(ids) == 0 {
+		return filterProxyHosts(hosts, filters), nil
+	}
+
+	byID := make(map[int]ProxyHost, len(hosts))
+	for _, host := range hosts {
+		byID[host.ID] = host
+	}
+
+	selected := make([]ProxyHost, 0, len(ids))
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		host, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("proxy host %d not found", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			selected = append(selected, host)
+		}
+	}
+	return selected, nil
+}
+
+var bulkUpdateCmd = &cobra.Command{
+	Use:   "bulk-update",
+	Short: "Change fields of many proxy hosts at once",
+	Long: `Apply the same field changes to all proxy hosts matching --filter
+expressions, or to the hosts given with --ids, for example to move every host
+from one backend to another:
+
+  bulk-update --filter forward_host=oldserver --set forward_host=newserver
+
+--set takes FIELD=VALUE like the set command. The planned changes are shown and
+must be confirmed; --dry-run only shows them. Every host is validated before
+anything is sent, and the hosts are updated concurrently.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		filterExprs, _ := cmd.Flags().GetStringArray("filter")
+		ids, _ := cmd.Flags().GetIntSlice("ids")
+		assignments, _ := cmd.Flags().GetStringArray("set")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		timeoutPerItem, _ := cmd.Flags().GetDuration("timeout-per-item")
+
+		if len(filterExprs) == 0 && len(ids) == 0 {
+			return fmt.Errorf("filter or ids is required")
+		}
+		if len(assignments) == 0 {
+			return fmt.Errorf("set is required")
+		}
+		for _, assignment := range assignments {
+			if !strings.Contains(assignment, "=") {
+				return fmt.Errorf("invalid assignment %q, expected field=value", assignment)
+			}
+		}
+
+		filters, err := parseFieldFilters(filterExprs)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+		hosts, err = selectBulkHosts(hosts, ids, filters)
+		if err != nil {
+			return err
+		}
+		if len(hosts) == 0 {
+			fmt.Fprintln(out, "No proxy hosts match the filters")
+			return nil
+		}
+
+		var changes []bulkChange
+		for _, host := range hosts {
+			change, err := planBulkChange(host, assignments)
+			if err != nil {
+				return fmt.Errorf("proxy host %d: %w", host.ID, err)
+			}
+			if len(change.Fields) == 0 {
+				fmt.Fprintf(out, "Proxy host %d %v is unchanged\n", host.ID, host.DomainNames)
+				continue
+			}
+			changes = append(changes, change)
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		for _, change := range changes {
+			fmt.Fprintf(out, "Proxy host %d %v:\n", change.Host.ID, change.Host.DomainNames)
+			for _, field := range change.Fields {
+				fmt.Fprintf(out, "  %s: %s -> %s\n", field, change.Before[field], change.After[field])
+			}
+		}
+		if dryRun {
+			fmt.Fprintf(out, "Dry run, %d proxy hosts would be updated\n", len(changes))
+			return nil
+		}
+		if !yes && !confirm(fmt.Sprintf("Update these %d proxy hosts?", len(changes))) {
+			return fmt.Errorf("aborted")
+		}
+
+		errs := runConcurrently(len(changes), parallel, func(i int) error {
+			return client.runWithTimeout(timeoutPerItem, func(client *APIClient) error {
+				_, err := client.UpdateProxyHost(changes[i].Host.ID, changes[i].Updated)
+				return err
+			})
+		})
+
+		var result BatchResult
+		for i, err := range errs {
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to update proxy host %d: %v\n", changes[i].Host.ID, err)
+				continue
+			}
+			fmt.Fprintf(out, "Successfully updated proxy host with ID: %d\n", changes[i].Host.ID)
+		}
+
+		fmt.Fprintf(out, "Bulk update finished: %s\n", result)
+		return result.Err()
+	},
+}
+
+func init() {
+	bulkUpdateCmd.Flags().StringArray("filter", nil, "Update hosts matching field=value or field!=value (repeatable, all must match)")
+	bulkUpdateCmd.Flags().IntSlice("ids", nil, "Update these proxy hosts (comma separated)")
+	bulkUpdateCmd.Flags().StringArray("set", nil, "Field change as FIELD=VALUE, like in the set command (repeatable)")
+	bulkUpdateCmd.Flags().Bool("dry-run", false, "Only show the planned changes")
+	bulkUpdateCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	bulkUpdateCmd.Flags().Int("parallel", 5, "Number of proxy hosts to update concurrently")
+	bulkUpdateCmd.Flags().Duration("timeout-per-item", 0, "Maximum time for updating a single proxy host, 0 for no limit")
+	bulkUpdateCmd.MarkFlagsMutuallyExclusive("filter", "ids")
+
+	rootCmd.AddCommand(bulkUpdateCmd)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// resolveCertificate returns the certificate given by ID, nice name or domain
+func resolveCertificate(client *APIClient, value string) (*Certificate, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return client.GetCertificate(id)
+	}
+
+	certs, err := client.ListCertificates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+	return findCertificateByName(certs, value)
+}
+
+// resolveProxyHost returns the proxy host given by ID or by one of its domains
+func resolveProxyHost(client *APIClient, value string) (*ProxyHost, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return client.GetProxyHost(id)
+	}
+
+	hosts, err := client.ListProxyHosts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proxy hosts: %w", err)
+	}
+	return selectProxyHostByDomain(hosts, value, false, 0)
+}
+
+var certificateAttachCmd = &cobra.Command{
+	Use:   "attach CERTIFICATE",
+	Short: "Enable HTTPS on a proxy host with a certificate",
+	Long: `Assign a certificate, given by ID, name or domain, to a proxy host given by ID
+or domain. All other settings of the host are kept, unless changed with
+--force-ssl, --http2 or --hsts.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		hostValue, _ := cmd.Flags().GetString("host")
+		if hostValue == "" {
+			return fmt.Errorf("host is required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		cert, err := resolveCertificate(client, args[0])
+		if err != nil {
+			return err
+		}
+		host, err := resolveProxyHost(client, hostValue)
+		if err != nil {
+			return err
+		}
+
+		if host.CertificateID != 0 && host.CertificateID != cert.ID {
+			fmt.Fprintf(out, "Replacing certificate %d of proxy host %d\n", host.CertificateID, host.ID)
+		}
+		host.CertificateID = cert.ID
+
+		if cmd.Flags().Changed("force-ssl") {
+			host.SslForced, _ = cmd.Flags().GetBool("force-ssl")
+		}
+		if cmd.Flags().Changed("http2") {
+			host.HTTP2Support, _ = cmd.Flags().GetBool("http2")
+		}
+		if cmd.Flags().Changed("hsts") {
+			host.HSTSEnabled, _ = cmd.Flags().GetBool("hsts")
+			if host.HSTSEnabled && !host.SslForced {
+				return fmt.Errorf("--hsts requires SSL to be forced, browsers ignore HSTS on plain HTTP; add --force-ssl")
+			}
+		}
+
+		if uncovered := uncoveredDomains(host.DomainNames, cert.DomainNames); len(uncovered) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: certificate %d %v does not cover %s\n", cert.ID, cert.DomainNames, strings.Join(uncovered, ", "))
+		}
+		if !host.SslForced {
+			fmt.Fprintln(os.Stderr, "Warning: SSL is not forced, HTTP requests will not be redirected to HTTPS. Use --force-ssl to redirect.")
+		}
+
+		updatedHost, err := client.UpdateProxyHost(host.ID, *host)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Attached certificate %d %q to proxy host %d %v\n", cert.ID, cert.NiceName, updatedHost.ID, updatedHost.DomainNames)
+		fmt.Fprintf(out, "SSL: %s\n", sslStatus(*updatedHost))
+		return nil
+	},
+}
+
+var certificateDetachCmd = &cobra.Command{
+	Use:   "detach",
+	Short: "Remove the certificate from a proxy host",
+	Long: `Remove the certificate from a proxy host given by ID or domain, so it serves
+plain HTTP only. Forced SSL and HSTS are switched off as well, since they make
+no sense without a certificate.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		hostValue, _ := cmd.Flags().GetString("host")
+		if hostValue == "" {
+			return fmt.Errorf("host is required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := resolveProxyHost(client, hostValue)
+		if err != nil {
+			return err
+		}
+		if host.CertificateID == 0 {
+			fmt.Fprintf(out, "Proxy host %d %v has no certificate\n", host.ID, host.DomainNames)
+			return nil
+		}
+
+		certID := host.CertificateID
+		host.CertificateID = 0
+		host.SslForced = false
+		host.HSTSEnabled = false
+		host.HSTSSubdomains = false
+
+		if _, err := client.UpdateProxyHost(host.ID, *host); err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Detached certificate %d from proxy host %d %v\n", certID, host.ID, host.DomainNames)
+		return nil
+	},
+}
+
+func init() {
+	certificateAttachCmd.Flags().String("host", "", "ID or domain of the proxy host (required)")
+	certificateAttachCmd.Flags().Bool("force-ssl", false, "Redirect HTTP requests to HTTPS")
+	certificateAttachCmd.Flags().Bool("http2", false, "Enable HTTP/2 for HTTPS connections")
+	certificateAttachCmd.Flags().Bool("hsts", false, "Send a Strict-Transport-Security header (requires --force-ssl)")
+
+	certificateDetachCmd.Flags().String("host", "", "ID or domain of the proxy host (required)")
+
+	certificateCmd.AddCommand(certificateAttachCmd)
+	certificateCmd.AddCommand(certificateDetachCmd)
+}
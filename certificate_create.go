@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CertificateRequestError is returned when NPM fails to issue a certificate.
+// Output holds the error NPM reported, usually the output of certbot.
+type CertificateRequestError struct {
+	Status int
+	Output string
+}
+
+func (e *CertificateRequestError) Error() string {
+	return fmt.Sprintf("certificate request failed, status: %d", e.Status)
+}
+
+// apiErrorMessage extracts the message of an NPM error response like
+// {"error": {"message": "..."}}, falling back to the raw body
+func apiErrorMessage(body []byte) string {
+	var response struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err == nil && response.Error.Message != "" {
+		return response.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// isTimeout reports whether a request failed because it took too long
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sameDomains reports whether two lists hold the same domain names in any order
+func sameDomains(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, domain := range a {
+		if !slices.ContainsFunc(b, func(other string) bool { return strings.EqualFold(domain, other) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// IssueCertificate requests a new certificate and waits until it is issued.
+// NPM answers the request only once certbot has finished, which may take
+// longer than the HTTP timeout; in that case the certificate list is polled
+// for the new certificate until the timeout expires.
+func (c *APIClient) IssueCertificate(cert Certificate, timeout time.Duration) (*Certificate, error) {
+	deadline := time.Now().Add(timeout)
+
+	before, err := c.ListCertificates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+	existing := make(map[int]bool, len(before))
+	for _, other := range before {
+		existing[other.ID] = true
+	}
+
+	jsonData, err := json.Marshal(cert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal certificate: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", "/nginx/certificates", bytes.NewBuffer(jsonData))
+	if err != nil && !isTimeout(err) {
+		return nil, err
+	}
+	if err == nil {
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, &CertificateRequestError{Status: resp.StatusCode, Output: apiErrorMessage(body)}
+		}
+
+		var created Certificate
+		if err := decodeJSON(resp.Body, &created); err != nil {
+			return nil, fmt.Errorf("failed to decode created certificate: %w", err)
+		}
+		if created.ExpiresOn != "" {
+			return &created, nil
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Waiting for the certificate to be issued...")
+	delay := 2 * time.Second
+	for {
+		certs, err := c.ListCertificates()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list certificates: %w", err)
+		}
+		for _, candidate := range certs {
+			if !existing[candidate.ID] && candidate.ExpiresOn != "" && sameDomains(candidate.DomainNames, cert.DomainNames) {
+				return &candidate, nil
+			}
+		}
+
+		if time.Now().Add(delay).After(deadline) {
+			return nil, fmt.Errorf("certificate for %s was not issued within %s, check the NPM logs", strings.Join(cert.DomainNames, ", "), timeout)
+		}
+		time.Sleep(delay)
+		if delay *= 2; delay > 15*time.Second {
+			delay = 15 * time.Second
+		}
+	}
+}
+
+var certificateCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Request a new certificate",
+	Long: `Request a new Let's Encrypt certificate for one or more domains and wait
+until it is issued. Let's Encrypt has to reach the domains over HTTP, so they
+must already point at NPM.
+
+If issuing fails, the error reported by NPM, usually the certbot output, is
+printed.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		letsEncrypt, _ := cmd.Flags().GetBool("letsencrypt")
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
+		email, _ := cmd.Flags().GetString("email")
+		agreeTOS, _ := cmd.Flags().GetBool("agree-tos")
+		name, _ := cmd.Flags().GetString("name")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		dnsProvider, _ := cmd.Flags().GetString("dns-provider")
+		credentialsFile, _ := cmd.Flags().GetString("dns-credentials-file")
+		propagationSeconds, _ := cmd.Flags().GetInt("propagation-seconds")
+		testReachability, _ := cmd.Flags().GetBool("test-reachability")
+
+		if !letsEncrypt {
+			return fmt.Errorf("--letsencrypt is required, it is the only provider certificates can be requested from")
+		}
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
+		}
+		if len(domains) == 0 {
+			return fmt.Errorf("domain is required")
+		}
+		if dnsProvider == "" {
+			if credentialsFile != "" || propagationSeconds != 0 {
+				return fmt.Errorf("--dns-credentials-file and --propagation-seconds require --dns-provider")
+			}
+			for _, domain := range domains {
+				if strings.HasPrefix(domain, "*.") {
+					return fmt.Errorf("wildcard domain %s can only be validated with the DNS challenge, give --dns-provider", domain)
+				}
+			}
+		} else if credentialsFile == "" {
+			return fmt.Errorf("--dns-provider requires --dns-credentials-file")
+		}
+		if testReachability && dnsProvider != "" {
+			return fmt.Errorf("--test-reachability checks the HTTP challenge and can't be combined with --dns-provider")
+		}
+		if propagationSeconds < 0 {
+			return fmt.Errorf("propagation-seconds must not be negative")
+		}
+		if !agreeTOS {
+			return fmt.Errorf("the Let's Encrypt terms of service must be accepted with --agree-tos")
+		}
+		if name == "" {
+			name = domains[0]
+		}
+
+		meta := CertificateMeta{LetsEncryptAgree: true}
+		if dnsProvider != "" {
+			credentials, err := os.ReadFile(credentialsFile)
+			if err != nil {
+				return fmt.Errorf("failed to read DNS credentials file: %w", err)
+			}
+			meta.DNSChallenge = true
+			meta.DNSProvider = dnsProvider
+			meta.DNSProviderCredentials = string(credentials)
+			meta.PropagationSeconds = propagationSeconds
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		// NPM fills in the account email in its UI, do the same
+		if email == "" {
+			user, err := client.GetCurrentUser()
+			if err != nil {
+				return fmt.Errorf("no --email given and the email of the current user is unknown: %w", err)
+			}
+			email = user.Email
+		}
+
+		meta.LetsEncryptEmail = email
+
+		if testReachability {
+			if err := checkReachability(client, domains); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(out, "Requesting Let's Encrypt certificate for %s...\n", strings.Join(domains, ", "))
+		cert, err := client.IssueCertificate(Certificate{
+			Provider:    "letsencrypt",
+			NiceName:    name,
+			DomainNames: domains,
+			Meta:        meta,
+		}, timeout)
+		var requestErr *CertificateRequestError
+		if errors.As(err, &requestErr) {
+			fmt.Fprintln(os.Stderr, "NPM reported:")
+			fmt.Fprintln(os.Stderr, requestErr.Output)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully created certificate with ID: %d\n", cert.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(cert.DomainNames, ", "))
+		fmt.Fprintf(out, "Expires: %s\n", cert.ExpiresOn)
+
+		return nil
+	},
+}
+
+func init() {
+	certificateCreateCmd.Flags().Bool("letsencrypt", false, "Request the certificate from Let's Encrypt")
+	certificateCreateCmd.Flags().StringSlice("domain", nil, "Domain name for the certificate (repeatable or comma separated)")
+	certificateCreateCmd.Flags().String("email", "", "Email for the Let's Encrypt account (default: email of the current user)")
+	certificateCreateCmd.Flags().Bool("agree-tos", false, "Accept the Let's Encrypt terms of service")
+	certificateCreateCmd.Flags().String("name", "", "Nice name of the certificate (default: the first domain)")
+	certificateCreateCmd.Flags().String("dns-provider", "", "Validate the domains with the DNS challenge of this certbot DNS plugin, like cloudflare")
+	certificateCreateCmd.Flags().String("dns-credentials-file", "", "Credentials file of the DNS plugin, as used by certbot")
+	certificateCreateCmd.Flags().Int("propagation-seconds", 0, "Seconds to wait for DNS changes to propagate (default: the plugin's default)")
+	certificateCreateCmd.Flags().Bool("test-reachability", false, "Check that the domains reach NPM on port 80 before requesting the certificate")
+	certificateCreateCmd.Flags().Duration("timeout", 5*time.Minute, "Maximum time to wait for the certificate to be issued")
+
+	certificateCmd.AddCommand(certificateCreateCmd)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// DNSProvider is a certbot DNS plugin supported by NPM for the DNS challenge
+type DNSProvider struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Credentials string `json:"credentials"`
+}
+
+// ListDNSProviders lists the DNS providers NPM supports, each with a template
+// of its credentials file
+func (c *APIClient) ListDNSProviders() ([]DNSProvider, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/certificates/dns-providers", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("this NPM version does not list DNS providers")
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list DNS providers, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var providers []DNSProvider
+	if err := decodeJSON(resp.Body, &providers); err != nil {
+		return nil, fmt.Errorf("failed to decode DNS providers: %w", err)
+	}
+	return providers, nil
+}
+
+var certificateDNSProvidersCmd = &cobra.Command{
+	Use:   "dns-providers [ID]",
+	Short: "List the DNS providers for the DNS challenge",
+	Long: `List the certbot DNS plugins NPM supports for --dns-provider of cert create,
+each with a template of the credentials file it expects.
+
+Given the ID of a provider, only its template is printed, ready to be saved as
+the file for --dns-credentials-file.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		providers, err := client.ListDNSProviders()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			for _, provider := range providers {
+				if strings.EqualFold(provider.ID, args[0]) {
+					if output == "json" {
+						return writeJSON(provider)
+					}
+					fmt.Fprintln(out, strings.TrimRight(provider.Credentials, "\n"))
+					return nil
+				}
+			}
+			return fmt.Errorf("unknown DNS provider %q, run cert dns-providers to list them", args[0])
+		}
+
+		if output == "json" {
+			return writeJSON(providers)
+		}
+
+		for i, provider := range providers {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "%s (%s)\n", provider.ID, provider.Name)
+			for _, line := range strings.Split(strings.TrimRight(provider.Credentials, "\n"), "\n") {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	certificateCmd.AddCommand(certificateDNSProvidersCmd)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+
+	"github.com/spf13/cobra"
+)
+
+// archiveSuffixPattern matches the version number certbot adds to the files in
+// its archive directory, like the 1 in fullchain1.pem
+var archiveSuffixPattern = regexp.MustCompile(`\d+(\.pem)$`)
+
+// DownloadCertificate downloads the files of a certificate as a zip archive
+func (c *APIClient) DownloadCertificate(id int) ([]byte, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/certificates/%d/download", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("certificate %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to download certificate, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	archive, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate archive: %w", err)
+	}
+	return archive, nil
+}
+
+// certificateFileName returns the name a file of a certificate archive is
+// written as, without directories and certbot's version number
+func certificateFileName(name string) string {
+	return archiveSuffixPattern.ReplaceAllString(path.Base(name), "$1")
+}
+
+// extractCertificateArchive writes the files of a certificate archive to dir
+// and returns their paths. Existing files are only replaced with overwrite.
+func extractCertificateArchive(archive []byte, dir string, overwrite bool) ([]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open certificate archive: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	var written []string
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		target := filepath.Join(dir, certificateFileName(file.Name))
+		if err := extractFile(file, target, flags); err != nil {
+			return written, err
+		}
+		written = append(written, target)
+	}
+	return written, nil
+}
+
+// extractFile writes one file of a certificate archive. Everything is written
+// readable only by the current user, as the archive contains the private key.
+func extractFile(file *zip.File, target string, flags int) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s from certificate archive: %w", file.Name, err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(target, flags, 0o600)
+	if os.IsExist(err) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", target)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return dst.Close()
+}
+
+var certificateDownloadCmd = &cobra.Command{
+	Use:   "download ID",
+	Short: "Download the certificate and key files of a certificate",
+	Long: `Download the PEM files of a certificate, including its private key, and
+write them to a directory, for example to use the certificate in other services.
+
+The files are named like certbot's live directory: cert.pem, chain.pem,
+fullchain.pem and privkey.pem. They are only readable by the current user.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		outDir, _ := cmd.Flags().GetString("out-dir")
+		force, _ := cmd.Flags().GetBool("force")
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		archive, err := client.DownloadCertificate(ids[0])
+		if err != nil {
+			return err
+		}
+
+		written, err := extractCertificateArchive(archive, outDir, force)
+		for _, path := range written {
+			fmt.Fprintf(out, "Wrote %s\n", path)
+		}
+		if err != nil {
+			return err
+		}
+		if len(written) == 0 {
+			return fmt.Errorf("certificate archive of certificate %d is empty", ids[0])
+		}
+		return nil
+	},
+}
+
+func init() {
+	certificateDownloadCmd.Flags().String("out-dir", ".", "Directory to write the certificate files to")
+	certificateDownloadCmd.Flags().Bool("force", false, "Overwrite existing files")
+
+	certificateCmd.AddCommand(certificateDownloadCmd)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// icsEscape escapes a text value for an iCalendar property
+func icsEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// writeICSLine writes an iCalendar content line, folded at 75 octets as RFC 5545 requires
+func writeICSLine(w io.Writer, line string) {
+	const limit = 75
+	for len(line) > limit {
+		// Don't split a multi-byte character
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		fmt.Fprintf(w, "%s\r\n", line[:cut])
+		line = " " + line[cut:]
+	}
+	fmt.Fprintf(w, "%s\r\n", line)
+}
+
+// writeExpiryCalendar writes an iCalendar file with an all-day event on the
+// expiry date of every certificate and an alarm alarmDays before it
+func writeExpiryCalendar(w io.Writer, certs []Certificate, alarmDays int, now time.Time) {
+	writeICSLine(w, "BEGIN:VCALENDAR")
+	writeICSLine(w, "VERSION:2.0")
+	writeICSLine(w, "PRODID:-//nginxproxymanager-cli//certificate expiry//EN")
+	writeICSLine(w, "CALSCALE:GREGORIAN")
+
+	for _, cert := range certs {
+		expiry, err := cert.Expiry()
+		if err != nil {
+			continue
+		}
+
+		domains := strings.Join(cert.DomainNames, ", ")
+		writeICSLine(w, "BEGIN:VEVENT")
+		writeICSLine(w, fmt.Sprintf("UID:certificate-%d-%s@nginxproxymanager-cli", cert.ID, expiry.UTC().Format("20060102")))
+		writeICSLine(w, "DTSTAMP:"+now.UTC().Format("20060102T150405Z"))
+		writeICSLine(w, "DTSTART;VALUE=DATE:"+expiry.Format("20060102"))
+		writeICSLine(w, "DTEND;VALUE=DATE:"+expiry.AddDate(0, 0, 1).Format("20060102"))
+		writeICSLine(w, "SUMMARY:"+icsEscape(fmt.Sprintf("Certificate %s expires (%s)", cert.NiceName, domains)))
+		writeICSLine(w, "DESCRIPTION:"+icsEscape(fmt.Sprintf("Certificate %d (%s) for %s expires on %s.", cert.ID, cert.Provider, domains, cert.ExpiresOn)))
+		if alarmDays > 0 {
+			writeICSLine(w, "BEGIN:VALARM")
+			writeICSLine(w, "ACTION:DISPLAY")
+			writeICSLine(w, fmt.Sprintf("TRIGGER:-P%dD", alarmDays))
+			writeICSLine(w, "DESCRIPTION:"+icsEscape(fmt.Sprintf("Certificate %s expires in %d days", cert.NiceName, alarmDays)))
+			writeICSLine(w, "END:VALARM")
+		}
+		writeICSLine(w, "END:VEVENT")
+	}
+
+	writeICSLine(w, "END:VCALENDAR")
+}
+
+var certificateExpiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "List certificates expiring soon, optionally as a calendar file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		withinValue, _ := cmd.Flags().GetString("within")
+		format, _ := cmd.Flags().GetString("format")
+		alarmDays, _ := cmd.Flags().GetInt("alarm-days")
+		file, _ := cmd.Flags().GetString("file")
+		if format != "text" && format != "ics" {
+			return fmt.Errorf("invalid format %q, expected text or ics", format)
+		}
+		if alarmDays < 0 {
+			return fmt.Errorf("alarm-days must not be negative")
+		}
+
+		within, err := parseDayDuration(withinValue)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		certs, err := client.ListCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+
+		now := time.Now()
+		certs = expiringCertificates(certs, now, within)
+
+		w := out
+		if file != "" {
+			f, err := os.Create(file)
+			if err != nil {
+				return fmt.Errorf("failed to create file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if format == "ics" {
+			writeExpiryCalendar(w, certs, alarmDays, now)
+		} else {
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tNAME\tDOMAINS\tEXPIRES\tDAYS LEFT")
+			for _, cert := range certs {
+				expiry, _ := cert.Expiry()
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\n",
+					cert.ID,
+					cert.NiceName,
+					strings.Join(cert.DomainNames, ","),
+					expiry.Format(time.DateOnly),
+					int(expiry.Sub(now).Hours()/24),
+				)
+			}
+			tw.Flush()
+		}
+
+		if file != "" {
+			fmt.Fprintf(os.Stderr, "Wrote %d certificates to %s\n", len(certs), file)
+		}
+		return nil
+	},
+}
+
+func init() {
+	certificateExpiringCmd.Flags().String("within", "30d", "Include certificates expiring within this time (e.g. 30d or 72h)")
+	certificateExpiringCmd.Flags().String("format", "text", "Output format (text or ics)")
+	certificateExpiringCmd.Flags().Int("alarm-days", 7, "Days before expiry for the calendar reminder, 0 for none")
+	certificateExpiringCmd.Flags().StringP("file", "f", "", "Write to a file instead of stdout")
+
+	certificateCmd.AddCommand(certificateExpiringCmd)
+}
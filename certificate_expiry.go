@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of certificate expiry, as used by Nagios plugins
+const (
+	exitExpiryWarning  = 1
+	exitExpiryCritical = 2
+)
+
+// certificateExpiryEntry is the expiry state of a certificate in the expiry report
+type certificateExpiryEntry struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Provider  string   `json:"provider"`
+	Domains   []string `json:"domain_names"`
+	ExpiresOn string   `json:"expires_on"`
+	DaysLeft  *int     `json:"days_left"`
+	Status    string   `json:"status"`
+}
+
+// evaluateCertificateExpiry rates every certificate as OK, WARNING or CRITICAL,
+// sorted soonest expiry first. Certificates with an unknown expiry are rated
+// UNKNOWN and listed last.
+func evaluateCertificateExpiry(certs []Certificate, now time.Time, warn, crit time.Duration) []certificateExpiryEntry {
+	report := make([]certificateExpiryEntry, 0, len(certs))
+	expiries := make(map[int]time.Time, len(certs))
+	for _, cert := range certs {
+		entry := certificateExpiryEntry{
+			ID:        cert.ID,
+			Name:      cert.NiceName,
+			Provider:  cert.Provider,
+			Domains:   cert.DomainNames,
+			ExpiresOn: cert.ExpiresOn,
+			Status:    "UNKNOWN",
+		}
+
+		if expiry, err := cert.Expiry(); err == nil {
+			expiries[cert.ID] = expiry
+			left := expiry.Sub(now)
+			days := int(left.Hours() / 24)
+			entry.DaysLeft = &days
+			switch {
+			case left < crit:
+				entry.Status = "CRITICAL"
+			case left < warn:
+				entry.Status = "WARNING"
+			default:
+				entry.Status = "OK"
+			}
+		}
+		report = append(report, entry)
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		a, aKnown := expiries[report[i].ID]
+		b, bKnown := expiries[report[j].ID]
+		if aKnown != bKnown {
+			return aKnown
+		}
+		return a.Before(b)
+	})
+	return report
+}
+
+var certificateExpiryCmd = &cobra.Command{
+	Use:   "expiry",
+	Short: "Report the expiry of all certificates and check it against thresholds",
+	Long: `Print every certificate with the days left until it expires, soonest first,
+rated OK, WARNING or CRITICAL against --warn and --crit.
+
+Like a Nagios plugin, the command exits with 2 if any certificate is critical,
+with 1 if any is in the warning range, and with 0 otherwise.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		warnValue, _ := cmd.Flags().GetString("warn")
+		critValue, _ := cmd.Flags().GetString("crit")
+		warn, err := parseDayDuration(warnValue)
+		if err != nil {
+			return err
+		}
+		crit, err := parseDayDuration(critValue)
+		if err != nil {
+			return err
+		}
+		if crit > warn {
+			return fmt.Errorf("--crit must not be longer than --warn")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		certs, err := client.ListCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+
+		report := evaluateCertificateExpiry(certs, time.Now(), warn, crit)
+		warning, critical := 0, 0
+		for _, entry := range report {
+			switch entry.Status {
+			case "WARNING":
+				warning++
+			case "CRITICAL":
+				critical++
+			}
+		}
+
+		if output == "json" {
+			if err := writeJSON(report); err != nil {
+				return err
+			}
+		} else {
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tDOMAINS\tEXPIRES\tDAYS LEFT\tSTATUS")
+			for _, entry := range report {
+				daysLeft := "-"
+				if entry.DaysLeft != nil {
+					daysLeft = fmt.Sprint(*entry.DaysLeft)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+					entry.ID,
+					entry.Name,
+					strings.Join(entry.Domains, ","),
+					entry.ExpiresOn,
+					daysLeft,
+					entry.Status,
+				)
+			}
+			w.Flush()
+		}
+
+		switch {
+		case critical > 0:
+			return &exitCodeError{Code: exitExpiryCritical, Err: fmt.Errorf("%d certificates expire within %s", critical, critValue)}
+		case warning > 0:
+			return &exitCodeError{Code: exitExpiryWarning, Err: fmt.Errorf("%d certificates expire within %s", warning, warnValue)}
+		}
+		return nil
+	},
+}
+
+func init() {
+	certificateExpiryCmd.Flags().String("warn", "21d", "Warn about certificates expiring within this time (e.g. 21d or 72h)")
+	certificateExpiryCmd.Flags().String("crit", "7d", "Report certificates expiring within this time as critical")
+
+	certificateCmd.AddCommand(certificateExpiryCmd)
+}
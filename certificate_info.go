@@ -0,0 +1,175 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// x509Details are the parsed details of an issued certificate
+type x509Details struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	SANs               []string  `json:"sans"`
+	NotBefore          time.Time `json:"not_before"`
+	NotAfter           time.Time `json:"not_after"`
+	SerialNumber       string    `json:"serial_number"`
+	KeyType            string    `json:"key_type"`
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	SHA256Fingerprint  string    `json:"sha256_fingerprint"`
+}
+
+// archiveCertificate returns the leaf certificate from a certificate archive.
+// cert.pem holds only the leaf, fullchain.pem starts with it.
+func archiveCertificate(archive []byte) (*x509.Certificate, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open certificate archive: %w", err)
+	}
+
+	for _, name := range []string{"cert.pem", "fullchain.pem"} {
+		for _, file := range reader.File {
+			if certificateFileName(file.Name) != name {
+				continue
+			}
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, err
+			}
+			block, _ := pem.Decode(content)
+			if block == nil || block.Type != "CERTIFICATE" {
+				return nil, fmt.Errorf("%s does not contain a PEM certificate", file.Name)
+			}
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+	return nil, fmt.Errorf("certificate archive contains no cert.pem or fullchain.pem")
+}
+
+// readZipFile returns the content of a file of a zip archive
+func readZipFile(file *zip.File) ([]byte, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from certificate archive: %w", file.Name, err)
+	}
+	defer src.Close()
+	return io.ReadAll(src)
+}
+
+// publicKeyType describes the algorithm and size of a certificate's key
+func publicKeyType(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// fingerprint formats a hash as colon separated uppercase hex, like openssl
+func fingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// describeX509 returns the details of a parsed certificate
+func describeX509(cert *x509.Certificate) x509Details {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sum := sha256.Sum256(cert.Raw)
+
+	return x509Details{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SANs:               sans,
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		SerialNumber:       fmt.Sprintf("%X", cert.SerialNumber),
+		KeyType:            publicKeyType(cert),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SHA256Fingerprint:  fingerprint(sum[:]),
+	}
+}
+
+var certificateInfoCmd = &cobra.Command{
+	Use:   "info ID",
+	Short: "Show the details of the issued certificate",
+	Long: `Download a certificate and print the details parsed from the certificate
+itself rather than the metadata NPM keeps: subject alternative names, issuer,
+validity, key type and fingerprint. A warning is printed when the domains NPM
+lists differ from the names in the certificate.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		cert, err := client.GetCertificate(ids[0])
+		if err != nil {
+			return err
+		}
+		archive, err := client.DownloadCertificate(cert.ID)
+		if err != nil {
+			return err
+		}
+		parsed, err := archiveCertificate(archive)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate %d: %w", cert.ID, err)
+		}
+		details := describeX509(parsed)
+
+		if uncovered := uncoveredDomains(cert.DomainNames, parsed.DNSNames); len(uncovered) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: NPM lists %s, but the certificate does not cover them\n", strings.Join(uncovered, ", "))
+		}
+
+		if output == "json" {
+			return writeJSON(details)
+		}
+
+		fmt.Fprintf(out, "ID: %d\n", cert.ID)
+		fmt.Fprintf(out, "Name: %s\n", cert.NiceName)
+		fmt.Fprintf(out, "Subject: %s\n", details.Subject)
+		fmt.Fprintf(out, "SANs: %s\n", strings.Join(details.SANs, ", "))
+		fmt.Fprintf(out, "Issuer: %s\n", details.Issuer)
+		fmt.Fprintf(out, "Not Before: %s\n", details.NotBefore.Format(time.RFC3339))
+		fmt.Fprintf(out, "Not After: %s (%d days left)\n", details.NotAfter.Format(time.RFC3339), int(time.Until(details.NotAfter).Hours()/24))
+		fmt.Fprintf(out, "Key Type: %s\n", details.KeyType)
+		fmt.Fprintf(out, "Signature Algorithm: %s\n", details.SignatureAlgorithm)
+		fmt.Fprintf(out, "Serial Number: %s\n", details.SerialNumber)
+		fmt.Fprintf(out, "SHA-256 Fingerprint: %s\n", details.SHA256Fingerprint)
+
+		return nil
+	},
+}
+
+func init() {
+	certificateCmd.AddCommand(certificateInfoCmd)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// reachabilityResults explains the results NPM reports for the HTTP challenge test
+var reachabilityResults = map[string]string{
+	"ok":         "reachable",
+	"no-host":    "domain does not resolve",
+	"failed":     "could not connect on port 80",
+	"404":        "reached a server that answered 404, probably not this NPM",
+	"wrong-data": "reached a server that is not this NPM",
+}
+
+// TestHTTPChallenge asks NPM to check that the domains reach it on port 80, as
+// Let's Encrypt's HTTP challenge requires. It returns the result per domain.
+func (c *APIClient) TestHTTPChallenge(domains []string) (map[string]string, error) {
+	encoded, err := json.Marshal(domains)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal domains: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/certificates/test-http?domains="+url.QueryEscape(string(encoded)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to test reachability, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var results map[string]string
+	if err := decodeJSON(resp.Body, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode reachability results: %w", err)
+	}
+	return results, nil
+}
+
+// explainReachability returns a readable description of a test result
+func explainReachability(result string) string {
+	if explanation, ok := reachabilityResults[result]; ok {
+		return explanation
+	}
+	return result
+}
+
+// checkReachability tests the domains and prints the result of each. It fails
+// when any domain is not reachable.
+func checkReachability(client *APIClient, domains []string) error {
+	results, err := client.TestHTTPChallenge(domains)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(results))
+	for domain := range results {
+		names = append(names, domain)
+	}
+	sort.Strings(names)
+
+	var unreachable []string
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DOMAIN\tRESULT")
+	for _, domain := range names {
+		fmt.Fprintf(w, "%s\t%s\n", domain, explainReachability(results[domain]))
+		if results[domain] != "ok" {
+			unreachable = append(unreachable, domain)
+		}
+	}
+	w.Flush()
+
+	if len(unreachable) > 0 {
+		return fmt.Errorf("%d of %d domains are not reachable over HTTP: %s", len(unreachable), len(names), strings.Join(unreachable, ", "))
+	}
+	return nil
+}
+
+var certificateTestReachabilityCmd = &cobra.Command{
+	Use:   "test-reachability",
+	Short: "Check that domains reach NPM on port 80 before requesting a certificate",
+	Long: `Ask NPM to check from the outside that the domains resolve and reach this NPM
+on port 80, as required by the HTTP challenge of Let's Encrypt. Running this
+before cert create avoids failed requests counting against the rate limits.
+
+Wildcard domains can't be tested, they always need the DNS challenge.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
+		}
+		if len(domains) == 0 {
+			return fmt.Errorf("domain is required")
+		}
+		for _, domain := range domains {
+			if strings.HasPrefix(domain, "*.") {
+				return fmt.Errorf("wildcard domain %s can't be tested, it needs the DNS challenge", domain)
+			}
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		return checkReachability(client, domains)
+	},
+}
+
+func init() {
+	certificateTestReachabilityCmd.Flags().StringSlice("domain", nil, "Domain name to test (repeatable or comma separated)")
+
+	certificateCmd.AddCommand(certificateTestReachabilityCmd)
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// rotatedHost is a host moved to another certificate by cert rotate
+type rotatedHost struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+}
+
+// rotationEntry records one cert rotate run so it can be rolled back
+type rotationEntry struct {
+	Time   time.Time     `json:"time"`
+	APIURL string        `json:"api_url"`
+	Old    int           `json:"old"`
+	New    int           `json:"new"`
+	Hosts  []rotatedHost `json:"hosts"`
+}
+
+// rotationJournalFile is the state file recording cert rotate runs
+const rotationJournalFile = "rotate-journal.json"
+
+// readRotationJournal reads all journal entries, oldest first
+func readRotationJournal() ([]rotationEntry, error) {
+	var entries []rotationEntry
+	err := readStateFile(rotationJournalFile, &entries)
+	return entries, err
+}
+
+// writeRotationJournal replaces the journal with the given entries
+func writeRotationJournal(entries []rotationEntry) error {
+	return writeStateFile(rotationJournalFile, entries)
+}
+
+// lastRotation returns the index of the newest journal entry of an NPM instance, or -1
+func lastRotation(entries []rotationEntry, apiURL string) int {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].APIURL == apiURL {
+			return i
+		}
+	}
+	return -1
+}
+
+// setHostCertificate points a host of any type at another certificate,
+// leaving all its other settings alone
+func setHostCertificate(client *APIClient, hosts *allHosts, hostType string, id, certID int) error {
+	var err error
+	switch hostType {
+	case "proxy host":
+		for _, host := range hosts.ProxyHosts {
+			if host.ID == id {
+				host.CertificateID = certID
+				_, err = client.UpdateProxyHost(id, host)
+				return err
+			}
+		}
+	case "redirection host":
+		for _, host := range hosts.RedirectionHosts {
+			if host.ID == id {
+				host.CertificateID = certID
+				_, err = client.UpdateRedirectionHost(id, host)
+				return err
+			}
+		}
+	case "404 host":
+		for _, host := range hosts.DeadHosts {
+			if host.ID == id {
+				host.CertificateID = certID
+				_, err = client.UpdateDeadHost(id, host)
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("%s %d not found", hostType, id)
+}
+
+// rotateCertificate moves the hosts from one certificate to another, one
+// after another. When an update fails, the hosts already moved are set back,
+// so either all hosts use the new certificate or none.
+func rotateCertificate(client *APIClient, hosts *allHosts, users []certificateUser, from, to int) ([]rotatedHost, error) {
+	var moved []rotatedHost
+	for _, user := range users {
+		if err := setHostCertificate(client, hosts, user.Type, user.ID, to); err != nil {
+			err = fmt.Errorf("failed to update %s %d: %w", user.Type, user.ID, err)
+
+			var stuck []string
+			for _, host := range moved {
+				if rollbackErr := setHostCertificate(client, hosts, host.Type, host.ID, from); rollbackErr != nil {
+					stuck = append(stuck, fmt.Sprintf("%s %d", host.Type, host.ID))
+				}
+			}
+			if len(stuck) > 0 {
+				return nil, fmt.Errorf("%w; rolling back failed, %s still use certificate %d", err, strings.Join(stuck, ", "), to)
+			}
+			return nil, fmt.Errorf("%w; the %d hosts already updated were rolled back", err, len(moved))
+		}
+		fmt.Fprintf(out, "Updated %s %d %v\n", user.Type, user.ID, user.Domains)
+		moved = append(moved, rotatedHost{user.Type, user.ID})
+	}
+	return moved, nil
+}
+
+// rollbackRotation moves the hosts of the last rotation back to the old
+// certificate. Hosts that no longer use the new certificate are left alone.
+func rollbackRotation(client *APIClient, entries []rotationEntry) error {
+	i := lastRotation(entries, apiURL)
+	if i < 0 {
+		return fmt.Errorf("no certificate rotation recorded, nothing to roll back")
+	}
+	entry := entries[i]
+
+	hosts, err := listAllHosts(client)
+	if err != nil {
+		return err
+	}
+	current := make(map[rotatedHost]certificateUser)
+	for _, user := range hosts.certificateUsage(entry.New) {
+		current[rotatedHost{user.Type, user.ID}] = user
+	}
+
+	var users []certificateUser
+	for _, host := range entry.Hosts {
+		user, ok := current[host]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Warning: %s %d no longer uses certificate %d, skipping it\n", host.Type, host.ID, entry.New)
+			continue
+		}
+		users = append(users, user)
+	}
+
+	if _, err := rotateCertificate(client, hosts, users, entry.New, entry.Old); err != nil {
+		return err
+	}
+	if err := writeRotationJournal(append(entries[:i], entries[i+1:]...)); err != nil {
+		return fmt.Errorf("the rotation was rolled back, but %w", err)
+	}
+
+	fmt.Fprintf(out, "Rolled back %d hosts from certificate %d to %d\n", len(users), entry.New, entry.Old)
+	return nil
+}
+
+var certificateRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Move every host from one certificate to another",
+	Long: `Find every proxy, redirection and 404 host using the old certificate and
+switch it to the new one, for example to replace an expiring custom certificate.
+
+The new certificate must cover the domains of all hosts and must not be
+expired, unless --force is given. If updating a host fails, the hosts already
+updated are switched back. Every rotation is recorded next to the config file;
+--rollback switches the hosts of the last rotation back to the old certificate.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		oldID, _ := cmd.Flags().GetInt("old")
+		newID, _ := cmd.Flags().GetInt("new")
+		rollback, _ := cmd.Flags().GetBool("rollback")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if rollback && (oldID != 0 || newID != 0 || dryRun) {
+			return fmt.Errorf("--rollback cannot be combined with --old, --new or --dry-run")
+		}
+		if !rollback && (oldID == 0 || newID == 0) {
+			return fmt.Errorf("old and new are required")
+		}
+		if !rollback && oldID == newID {
+			return fmt.Errorf("old and new must be different certificates")
+		}
+
+		entries, err := readRotationJournal()
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if rollback {
+			return rollbackRotation(client, entries)
+		}
+
+		newCert, err := client.GetCertificate(newID)
+		if err != nil {
+			return err
+		}
+		hosts, err := listAllHosts(client)
+		if err != nil {
+			return err
+		}
+		users := hosts.certificateUsage(oldID)
+		if len(users) == 0 {
+			fmt.Fprintf(out, "No hosts use certificate %d\n", oldID)
+			return nil
+		}
+
+		var problems []string
+		if expiry, err := newCert.Expiry(); err == nil && expiry.Before(time.Now()) {
+			problems = append(problems, fmt.Sprintf("certificate %d expired on %s", newCert.ID, expiry.Format(time.DateOnly)))
+		}
+		for _, user := range users {
+			if uncovered := uncoveredDomains(user.Domains, newCert.DomainNames); len(uncovered) > 0 {
+				problems = append(problems, fmt.Sprintf("certificate %d does not cover %s of %s %d", newCert.ID, strings.Join(uncovered, ", "), user.Type, user.ID))
+			}
+		}
+		if len(problems) > 0 {
+			if !force {
+				return fmt.Errorf("%s. Use --force if this is intended", strings.Join(problems, "; "))
+			}
+			for _, problem := range problems {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", problem)
+			}
+		}
+
+		fmt.Fprintf(out, "Hosts using certificate %d, to be moved to certificate %d %q:\n", oldID, newCert.ID, newCert.NiceName)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tID\tDOMAINS")
+		for _, user := range users {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", user.Type, user.ID, strings.Join(user.Domains, ","))
+		}
+		w.Flush()
+
+		if dryRun {
+			fmt.Fprintf(out, "Dry run, %d hosts would be updated\n", len(users))
+			return nil
+		}
+		if !yes && !confirm(fmt.Sprintf("Move these %d hosts to certificate %d?", len(users), newCert.ID)) {
+			return fmt.Errorf("aborted")
+		}
+
+		moved, err := rotateCertificate(client, hosts, users, oldID, newCert.ID)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, rotationEntry{
+			Time:   time.Now().UTC(),
+			APIURL: apiURL,
+			Old:    oldID,
+			New:    newCert.ID,
+			Hosts:  moved,
+		})
+		if err := writeRotationJournal(entries); err != nil {
+			return fmt.Errorf("the hosts were moved, but %w", err)
+		}
+
+		fmt.Fprintf(out, "Rotated %d hosts from certificate %d to %d\n", len(moved), oldID, newCert.ID)
+		return nil
+	},
+}
+
+func init() {
+	certificateRotateCmd.Flags().Int("old", 0, "ID of the certificate to replace")
+	certificateRotateCmd.Flags().Int("new", 0, "ID of the certificate to use instead")
+	certificateRotateCmd.Flags().Bool("dry-run", false, "Only show the hosts that would be updated")
+	certificateRotateCmd.Flags().Bool("rollback", false, "Move the hosts of the last rotation back to the old certificate")
+	certificateRotateCmd.Flags().Bool("force", false, "Rotate even if the new certificate is expired or doesn't cover all domains")
+	certificateRotateCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	certificateCmd.AddCommand(certificateRotateCmd)
+}
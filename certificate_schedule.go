@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// renewalSlot is the planned renewal time of a certificate
+type renewalSlot struct {
+	Cert Certificate
+	At   time.Time
+}
+
+// planRenewals spreads the renewals of the certificates over time. Each
+// certificate is renewed lead before its expiry, or tomorrow when that is
+// already past, starting at the start time of day. Renewals are stagger apart
+// so they don't all hit Let's Encrypt and NPM at the same moment.
+func planRenewals(certs []Certificate, now time.Time, lead, start, stagger time.Duration) []renewalSlot {
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+
+	var slots []renewalSlot
+	for i, cert := range certs {
+		expiry, err := cert.Expiry()
+		if err != nil {
+			continue
+		}
+
+		day := expiry.In(now.Location()).Add(-lead)
+		day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, now.Location())
+		if day.Before(tomorrow) {
+			day = tomorrow
+		}
+
+		slots = append(slots, renewalSlot{Cert: cert, At: day.Add(start + time.Duration(i)*stagger)})
+	}
+	return slots
+}
+
+// shellQuote quotes a word for the shell when it contains special characters
+func shellQuote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n'\"\\$`&|;<>()*?[]#~!{}") {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// renewCommand returns the command line that renews a certificate
+func renewCommand(id int) string {
+	binary, err := os.Executable()
+	if err != nil {
+		binary = "nginxproxymanager-cli"
+	}
+
+	args := []string{shellQuote(binary)}
+	if profileName != "" {
+		args = append(args, "--profile", shellQuote(profileName))
+	}
+	args = append(args, "certificate", "renew", "--id", fmt.Sprint(id))
+	return strings.Join(args, " ")
+}
+
+// writeCronSchedule prints a crontab entry for every planned renewal
+func writeCronSchedule(slots []renewalSlot) {
+	fmt.Fprintln(out, "# Certificate renewals planned by nginxproxymanager-cli")
+	fmt.Fprintln(out, "# The API URL and credentials must be available to cron, e.g. as NPM_* variables or a profile")
+	for _, slot := range slots {
+		fmt.Fprintf(out, "# %s (%s) expires %s\n", slot.Cert.NiceName, strings.Join(slot.Cert.DomainNames, ", "), slot.Cert.ExpiresOn)
+		fmt.Fprintf(out, "%d %d %d %d * %s\n", slot.At.Minute(), slot.At.Hour(), slot.At.Day(), int(slot.At.Month()), renewCommand(slot.Cert.ID))
+	}
+}
+
+// writeSystemdSchedule prints a service and a timer unit for every planned renewal
+func writeSystemdSchedule(slots []renewalSlot) {
+	for i, slot := range slots {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		name := fmt.Sprintf("npm-cert-renew-%d", slot.Cert.ID)
+		description := fmt.Sprintf("Renew certificate %d (%s)", slot.Cert.ID, strings.Join(slot.Cert.DomainNames, ", "))
+
+		fmt.Fprintf(out, "# %s.service\n", name)
+		fmt.Fprintln(out, "[Unit]")
+		fmt.Fprintf(out, "Description=%s\n", description)
+		fmt.Fprintln(out, "Wants=network-online.target")
+		fmt.Fprintln(out, "After=network-online.target")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "[Service]")
+		fmt.Fprintln(out, "Type=oneshot")
+		fmt.Fprintf(out, "ExecStart=%s\n", renewCommand(slot.Cert.ID))
+		fmt.Fprintln(out)
+
+		fmt.Fprintf(out, "# %s.timer\n", name)
+		fmt.Fprintln(out, "[Unit]")
+		fmt.Fprintf(out, "Description=%s, certificate expires %s\n", description, slot.Cert.ExpiresOn)
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "[Timer]")
+		fmt.Fprintf(out, "OnCalendar=%s\n", slot.At.Format("2006-01-02 15:04:00"))
+		fmt.Fprintln(out, "Persistent=true")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "[Install]")
+		fmt.Fprintln(out, "WantedBy=timers.target")
+	}
+}
+
+var certificateScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Print cron entries or systemd timers that renew expiring certificates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		withinValue, _ := cmd.Flags().GetString("within")
+		leadValue, _ := cmd.Flags().GetString("lead")
+		format, _ := cmd.Flags().GetString("format")
+		startValue, _ := cmd.Flags().GetString("start")
+		stagger, _ := cmd.Flags().GetDuration("stagger")
+		if format != "cron" && format != "systemd" {
+			return fmt.Errorf("invalid format %q, expected cron or systemd", format)
+		}
+
+		within, err := parseDayDuration(withinValue)
+		if err != nil {
+			return err
+		}
+		lead, err := parseDayDuration(leadValue)
+		if err != nil {
+			return err
+		}
+		startTime, err := time.Parse("15:04", startValue)
+		if err != nil {
+			return fmt.Errorf("invalid start time %q, expected HH:MM", startValue)
+		}
+		start := time.Duration(startTime.Hour())*time.Hour + time.Duration(startTime.Minute())*time.Minute
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		certs, err := client.ListCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+
+		// Only Let's Encrypt certificates can be renewed by NPM
+		renewable := letsEncryptCertificates(certs)
+
+		slots := planRenewals(expiringCertificates(renewable, time.Now(), within), time.Now(), lead, start, stagger)
+		if len(slots) == 0 {
+			fmt.Fprintf(os.Stderr, "No Let's Encrypt certificates expire within %s\n", withinValue)
+			return nil
+		}
+
+		if format == "systemd" {
+			writeSystemdSchedule(slots)
+		} else {
+			writeCronSchedule(slots)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	certificateScheduleCmd.Flags().String("within", "21d", "Include certificates expiring within this time (e.g. 21d or 72h)")
+	certificateScheduleCmd.Flags().String("lead", "7d", "Renew certificates this long before they expire")
+	certificateScheduleCmd.Flags().String("format", "cron", "Output format (cron or systemd)")
+	certificateScheduleCmd.Flags().String("start", "03:00", "Time of day of the first renewal")
+	certificateScheduleCmd.Flags().Duration("stagger", 10*time.Minute, "Time between consecutive renewals")
+
+	certificateCmd.AddCommand(certificateScheduleCmd)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// certificateUser is a host of any type that uses a certificate
+type certificateUser struct {
+	Type      string   `json:"type"`
+	ID        int      `json:"id"`
+	Domains   []string `json:"domain_names"`
+	Enabled   bool     `json:"enabled"`
+	SslForced bool     `json:"ssl_forced"`
+}
+
+// certificateUsage returns the proxy, redirection and 404 hosts using a certificate
+func (h *allHosts) certificateUsage(certID int) []certificateUser {
+	var users []certificateUser
+	for _, host := range h.ProxyHosts {
+		if host.CertificateID == certID {
+			users = append(users, certificateUser{"proxy host", host.ID, host.DomainNames, host.Enabled, host.SslForced})
+		}
+	}
+	for _, host := range h.RedirectionHosts {
+		if host.CertificateID == certID {
+			users = append(users, certificateUser{"redirection host", host.ID, host.DomainNames, host.Enabled, host.SslForced})
+		}
+	}
+	for _, host := range h.DeadHosts {
+		if host.CertificateID == certID {
+			users = append(users, certificateUser{"404 host", host.ID, host.DomainNames, host.Enabled, host.SslForced})
+		}
+	}
+	return users
+}
+
+var certificateUsageCmd = &cobra.Command{
+	Use:   "usage CERTIFICATE",
+	Short: "Show the hosts using a certificate",
+	Long: `List the proxy, redirection and 404 hosts that use a certificate, given by ID,
+name or domain, to see what breaks before deleting or replacing it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		cert, err := resolveCertificate(client, args[0])
+		if err != nil {
+			return err
+		}
+		hosts, err := listAllHosts(client)
+		if err != nil {
+			return err
+		}
+		users := hosts.certificateUsage(cert.ID)
+
+		if output == "json" {
+			if users == nil {
+				users = []certificateUser{}
+			}
+			return writeJSON(struct {
+				Certificate *Certificate      `json:"certificate"`
+				Hosts       []certificateUser `json:"hosts"`
+			}{cert, users})
+		}
+
+		fmt.Fprintf(out, "Certificate %d %q %v\n", cert.ID, cert.NiceName, cert.DomainNames)
+		if len(users) == 0 {
+			fmt.Fprintln(out, "Not used by any host")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tID\tDOMAINS\tENABLED\tSSL FORCED")
+		for _, user := range users {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%t\t%t\n", user.Type, user.ID, strings.Join(user.Domains, ","), user.Enabled, user.SslForced)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+func init() {
+	certificateCmd.AddCommand(certificateUsageCmd)
+}
@@ -0,0 +1,836 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Certificate represents an SSL certificate managed by Nginx Proxy Manager
+type Certificate struct {
+	ID          int             `json:"id"`
+	CreatedOn   string          `json:"created_on"`
+	ModifiedOn  string          `json:"modified_on"`
+	Provider    string          `json:"provider"`
+	NiceName    string          `json:"nice_name"`
+	DomainNames []string        `json:"domain_names"`
+	ExpiresOn   string          `json:"expires_on"`
+	Meta        CertificateMeta `json:"meta"`
+}
+
+// CertificateMeta holds the provider specific certificate settings
+type CertificateMeta struct {
+	LetsEncryptEmail string `json:"letsencrypt_email,omitempty"`
+	LetsEncryptAgree bool   `json:"letsencrypt_agree,omitempty"`
+	DNSChallenge     bool   `json:"dns_challenge,omitempty"`
+	DNSProvider      string `json:"dns_provider,omitempty"`
+	// DNSProviderCredentials is the certbot credentials file of the DNS provider
+	DNSProviderCredentials string `json:"dns_provider_credentials,omitempty"`
+	PropagationSeconds     int    `json:"propagation_seconds,omitempty"`
+}
+
+// expiresOnLayouts are the timestamp formats NPM uses for expires_on
+var expiresOnLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// Expiry parses the expiry time of the certificate
+func (cert Certificate) Expiry() (time.Time, error) {
+	for _, layout := range expiresOnLayouts {
+		if t, err := time.Parse(layout, cert.ExpiresOn); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q for certificate %d", cert.ExpiresOn, cert.ID)
+}
+
+// parseDayDuration parses a duration that may also be given in days like 21d
+func parseDayDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// expiringCertificates returns the certificates expiring before now+within,
+// soonest first. Certificates with an unknown expiry are left out.
+func expiringCertificates(certs []Certificate, now time.Time, within time.Duration) []Certificate {
+	deadline := now.Add(within)
+
+	var expiring []Certificate
+	for _, cert := range certs {
+		if expiry, err := cert.Expiry(); err == nil && expiry.Before(deadline) {
+			expiring = append(expiring, cert)
+		}
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		a, _ := expiring[i].Expiry()
+		b, _ := expiring[j].Expiry()
+		return a.Before(b)
+	})
+
+	return expiring
+}
+
+// ListCertificates lists all certificates
+func (c *APIClient) ListCertificates() ([]Certificate, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/certificates", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list certificates, status: %d", resp.StatusCode)
+	}
+
+	var certs []Certificate
+	if err := decodeJSON(resp.Body, &certs); err != nil {
+		return nil, fmt.Errorf("failed to decode certificates: %w", err)
+	}
+
+	return certs, nil
+}
+
+// GetCertificate fetches a single certificate by ID
+func (c *APIClient) GetCertificate(id int) (*Certificate, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/certificates/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("certificate %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get certificate, status: %d", resp.StatusCode)
+	}
+
+	var cert Certificate
+	if err := decodeJSON(resp.Body, &cert); err != nil {
+		return nil, fmt.Errorf("failed to decode certificate: %w", err)
+	}
+
+	return &cert, nil
+}
+
+// CreateCertificate requests a new certificate. For Let's Encrypt certificates
+// NPM only responds once the certificate has been issued.
+func (c *APIClient) CreateCertificate(cert Certificate) (*Certificate, error) {
+	jsonData, err := json.Marshal(cert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal certificate: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", "/nginx/certificates", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create certificate, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var createdCert Certificate
+	if err := decodeJSON(resp.Body, &createdCert); err != nil {
+		return nil, fmt.Errorf("failed to decode created certificate: %w", err)
+	}
+
+	return &createdCert, nil
+}
+
+// UpdateCertificate updates an existing certificate
+func (c *APIClient) UpdateCertificate(id int, cert Certificate) (*Certificate, error) {
+	jsonData, err := json.Marshal(cert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal certificate: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/nginx/certificates/%d", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update certificate, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var updatedCert Certificate
+	if err := decodeJSON(resp.Body, &updatedCert); err != nil {
+		return nil, fmt.Errorf("failed to decode updated certificate: %w", err)
+	}
+
+	return &updatedCert, nil
+}
+
+// RenewCertificate renews a Let's Encrypt certificate. NPM only responds once
+// the renewal has finished.
+func (c *APIClient) RenewCertificate(id int) (*Certificate, error) {
+	resp, err := c.makeAuthenticatedRequest("POST", fmt.Sprintf("/nginx/certificates/%d/renew", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("certificate %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to renew certificate, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var cert Certificate
+	if err := decodeJSON(resp.Body, &cert); err != nil {
+		return nil, fmt.Errorf("failed to decode renewed certificate: %w", err)
+	}
+
+	return &cert, nil
+}
+
+// DeleteCertificate deletes a certificate
+func (c *APIClient) DeleteCertificate(id int) error {
+	resp, err := c.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/nginx/certificates/%d", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("certificate %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete certificate, status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CertificateValidation is the result of validating certificate files with NPM
+type CertificateValidation struct {
+	Certificate struct {
+		CN     string `json:"cn"`
+		Issuer string `json:"issuer"`
+		Dates  struct {
+			From int64 `json:"from"`
+			To   int64 `json:"to"`
+		} `json:"dates"`
+	} `json:"certificate"`
+	CertificateKey bool `json:"certificate_key"`
+}
+
+// certificateFiles builds a multipart form from the given form field names and file paths
+func certificateFiles(files map[string]string) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for field, path := range files {
+		if path == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		part, err := writer.CreateFormFile(field, filepath.Base(path))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create form file: %w", err)
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, "", fmt.Errorf("failed to write form file: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to finish form: %w", err)
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
+
+// ValidateCertificate checks certificate files with NPM without creating a certificate
+func (c *APIClient) ValidateCertificate(certFile, keyFile, intermediateFile string) (*CertificateValidation, error) {
+	body, contentType, err := certificateFiles(map[string]string{
+		"certificate":              certFile,
+		"certificate_key":          keyFile,
+		"intermediate_certificate": intermediateFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+
+	resp, err := c.makeAuthenticatedRequestWithHeaders("POST", "/nginx/certificates/validate", body, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("certificate validation failed, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var validation CertificateValidation
+	if err := decodeJSON(resp.Body, &validation); err != nil {
+		return nil, fmt.Errorf("failed to decode validation result: %w", err)
+	}
+
+	return &validation, nil
+}
+
+// UploadCertificate uploads the files of a custom certificate created with provider "other"
+func (c *APIClient) UploadCertificate(id int, certFile, keyFile, intermediateFile string) error {
+	body, contentType, err := certificateFiles(map[string]string{
+		"certificate":              certFile,
+		"certificate_key":          keyFile,
+		"intermediate_certificate": intermediateFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+
+	resp, err := c.makeAuthenticatedRequestWithHeaders("POST", fmt.Sprintf("/nginx/certificates/%d/upload", id), body, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload certificate, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// pemDNSNames returns the DNS names of the first certificate in a PEM file
+func pemDNSNames(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s does not contain a PEM certificate", path)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return cert.DNSNames, nil
+}
+
+var certificateCmd = &cobra.Command{
+	Use:     "certificate",
+	Aliases: []string{"cert"},
+	Short:   "Manage SSL certificates",
+}
+
+var certificateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all certificates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		allProfiles, err := checkAllProfiles(cmd)
+		if err != nil {
+			return err
+		}
+
+		var results []profileResult[Certificate]
+		if allProfiles {
+			results, err = fanOutProfiles(func(client *APIClient) ([]Certificate, error) {
+				return client.ListCertificates()
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			client, err := newAuthenticatedClient()
+			if err != nil {
+				return err
+			}
+
+			certs, err := client.ListCertificates()
+			if err != nil {
+				return fmt.Errorf("failed to list certificates: %w", err)
+			}
+			results = []profileResult[Certificate]{{Items: certs}}
+		}
+
+		if output == "json" {
+			if !allProfiles {
+				return writeJSON(append([]Certificate{}, results[0].Items...))
+			}
+
+			outputs := make([]profileOutput, 0, len(results))
+			for _, result := range results {
+				profileOut := profileOutput{Profile: result.Profile}
+				if result.Err != nil {
+					profileOut.Error = result.Err.Error()
+				} else {
+					profileOut.Items = append([]Certificate{}, result.Items...)
+				}
+				outputs = append(outputs, profileOut)
+			}
+			if err := writeJSON(outputs); err != nil {
+				return err
+			}
+			return reportProfileErrors(results)
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		if allProfiles {
+			fmt.Fprint(w, "PROFILE\t")
+		}
+		fmt.Fprintln(w, "ID\tNAME\tPROVIDER\tDOMAINS\tEXPIRES")
+		for _, result := range results {
+			for _, cert := range result.Items {
+				if allProfiles {
+					fmt.Fprintf(w, "%s\t", result.Profile)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+					cert.ID,
+					cert.NiceName,
+					cert.Provider,
+					strings.Join(cert.DomainNames, ","),
+					cert.ExpiresOn,
+				)
+			}
+		}
+		w.Flush()
+
+		if allProfiles {
+			return reportProfileErrors(results)
+		}
+		return nil
+	},
+}
+
+var certificateGetCmd = &cobra.Command{
+	Use:   "get ID",
+	Short: "Show a certificate",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		cert, err := client.GetCertificate(ids[0])
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(cert)
+		}
+
+		fmt.Fprintf(out, "ID: %d\n", cert.ID)
+		fmt.Fprintf(out, "Name: %s\n", cert.NiceName)
+		fmt.Fprintf(out, "Provider: %s\n", cert.Provider)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(cert.DomainNames, ", "))
+		fmt.Fprintf(out, "Expires: %s\n", cert.ExpiresOn)
+		if cert.Meta.LetsEncryptEmail != "" {
+			fmt.Fprintf(out, "Let's Encrypt Email: %s\n", cert.Meta.LetsEncryptEmail)
+		}
+		if cert.Meta.DNSChallenge {
+			fmt.Fprintf(out, "DNS Challenge: %s\n", cert.Meta.DNSProvider)
+		}
+		fmt.Fprintf(out, "Created: %s\n", cert.CreatedOn)
+		fmt.Fprintf(out, "Modified: %s\n", cert.ModifiedOn)
+
+		return nil
+	},
+}
+
+var certificateDeleteCmd = &cobra.Command{
+	Use:   "delete ID",
+	Short: "Delete a certificate",
+	Long: `Delete a certificate. Certificates still used by a proxy, redirection or 404
+host are not deleted unless --force is given, as NPM would leave those hosts
+pointing at a missing certificate.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		id := ids[0]
+		force, _ := cmd.Flags().GetBool("force")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		cert, err := client.GetCertificate(id)
+		if err != nil {
+			return err
+		}
+
+		hosts, err := listAllHosts(client)
+		if err != nil {
+			return err
+		}
+		if users := hosts.certificateUsers(id); len(users) > 0 {
+			var lines []string
+			for _, user := range users {
+				lines = append(lines, fmt.Sprintf("  %s (%s)", user, user.Domain))
+			}
+			if !force {
+				return fmt.Errorf("certificate %d is used by %d hosts, use --force to delete it anyway:\n%s",
+					id, len(users), strings.Join(lines, "\n"))
+			}
+			fmt.Fprintf(os.Stderr, "Warning: certificate %d is used by %d hosts:\n%s\n", id, len(users), strings.Join(lines, "\n"))
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Delete certificate %d (%s)?", cert.ID, cert.NiceName)) {
+			return fmt.Errorf("aborted")
+		}
+
+		if err := client.DeleteCertificate(id); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully deleted certificate with ID: %d\n", id)
+		return nil
+	},
+}
+
+var certificateUploadCmd = &cobra.Command{
+	Use:   "upload",
+	Short: "Upload a custom certificate",
+	Long: `Upload a certificate managed outside of NPM, like one issued by an internal CA.
+
+The files are validated with NPM first. A custom certificate is then created
+and the files are uploaded to it; if the upload fails, the empty certificate is
+deleted again.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		name, _ := cmd.Flags().GetString("name")
+		certFile, _ := cmd.Flags().GetString("cert")
+		keyFile, _ := cmd.Flags().GetString("key")
+		intermediateFile, _ := cmd.Flags().GetString("intermediate")
+		name = strings.TrimSpace(name)
+		if name == "" || certFile == "" || keyFile == "" {
+			return fmt.Errorf("name, cert and key are required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		validation, err := client.ValidateCertificate(certFile, keyFile, intermediateFile)
+		if err != nil {
+			return err
+		}
+		if !validation.CertificateKey {
+			return fmt.Errorf("certificate key does not match the certificate")
+		}
+
+		domains, err := pemDNSNames(certFile)
+		if err != nil {
+			return fmt.Errorf("failed to read domains of %s: %w", certFile, err)
+		}
+
+		cert, err := client.CreateCertificate(Certificate{
+			Provider:    "other",
+			NiceName:    name,
+			DomainNames: domains,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := client.UploadCertificate(cert.ID, certFile, keyFile, intermediateFile); err != nil {
+			if deleteErr := client.DeleteCertificate(cert.ID); deleteErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to delete the empty certificate %d: %v\n", cert.ID, deleteErr)
+			}
+			return err
+		}
+
+		if uploaded, err := client.GetCertificate(cert.ID); err == nil {
+			cert = uploaded
+		}
+
+		fmt.Fprintf(out, "Successfully uploaded certificate with ID: %d\n", cert.ID)
+		fmt.Fprintf(out, "Name: %s\n", cert.NiceName)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(cert.DomainNames, ", "))
+		if cert.ExpiresOn != "" {
+			fmt.Fprintf(out, "Expires: %s\n", cert.ExpiresOn)
+		} else {
+			fmt.Fprintf(out, "Expires: %s\n", time.Unix(validation.Certificate.Dates.To, 0).Format(time.RFC3339))
+		}
+
+		return nil
+	},
+}
+
+var certificateRenameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Change the nice name of a certificate",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		id, _ := cmd.Flags().GetInt("id")
+		name, _ := cmd.Flags().GetString("name")
+		name = strings.TrimSpace(name)
+		if id == 0 || name == "" {
+			return fmt.Errorf("id and a non-empty name are required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		cert, err := client.GetCertificate(id)
+		if err != nil {
+			return fmt.Errorf("failed to get certificate: %w", err)
+		}
+
+		oldName := cert.NiceName
+		cert.NiceName = name
+
+		updatedCert, err := client.UpdateCertificate(id, *cert)
+		if err != nil {
+			return fmt.Errorf("failed to rename certificate: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully renamed certificate %d\n", updatedCert.ID)
+		fmt.Fprintf(out, "Old name: %s\n", oldName)
+		fmt.Fprintf(out, "New name: %s\n", updatedCert.NiceName)
+
+		return nil
+	},
+}
+
+// letsEncryptCertificates returns the certificates NPM can renew itself
+func letsEncryptCertificates(certs []Certificate) []Certificate {
+	var renewable []Certificate
+	for _, cert := range certs {
+		if cert.Provider == "letsencrypt" {
+			renewable = append(renewable, cert)
+		}
+	}
+	return renewable
+}
+
+var certificateRenewCmd = &cobra.Command{
+	Use:   "renew [ID]",
+	Short: "Renew Let's Encrypt certificates",
+	Long: `Renew a Let's Encrypt certificate given by ID, or with --all every Let's
+Encrypt certificate, optionally only those expiring within --expiring-within.
+
+Certificates are renewed one after another with progress reported for each;
+the command exits non-zero if any renewal failed, which suits cron jobs.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		id, _ := cmd.Flags().GetInt("id")
+		all, _ := cmd.Flags().GetBool("all")
+		withinValue, _ := cmd.Flags().GetString("expiring-within")
+
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			if id != 0 {
+				return fmt.Errorf("give the ID either as argument or with --id")
+			}
+			id = ids[0]
+		}
+		if all == (id != 0) {
+			return fmt.Errorf("either an ID or --all is required")
+		}
+		if withinValue != "" && !all {
+			return fmt.Errorf("--expiring-within requires --all")
+		}
+
+		var within time.Duration
+		if withinValue != "" {
+			if within, err = parseDayDuration(withinValue); err != nil {
+				return err
+			}
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if !all {
+			cert, err := client.RenewCertificate(id)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "Successfully renewed certificate %d\n", cert.ID)
+			fmt.Fprintf(out, "Domains: %s\n", strings.Join(cert.DomainNames, ", "))
+			fmt.Fprintf(out, "Expires: %s\n", cert.ExpiresOn)
+			return nil
+		}
+
+		certs, err := client.ListCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+		certs = letsEncryptCertificates(certs)
+		if withinValue != "" {
+			certs = expiringCertificates(certs, time.Now(), within)
+		}
+		if len(certs) == 0 {
+			if withinValue != "" {
+				fmt.Fprintf(out, "No Let's Encrypt certificates expire within %s\n", withinValue)
+			} else {
+				fmt.Fprintln(out, "No Let's Encrypt certificates found")
+			}
+			return nil
+		}
+
+		var result BatchResult
+		for i, cert := range certs {
+			fmt.Fprintf(out, "[%d/%d] Renewing certificate %d (%s)...\n", i+1, len(certs), cert.ID, strings.Join(cert.DomainNames, ", "))
+			renewed, err := client.RenewCertificate(cert.ID)
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to renew certificate %d: %v\n", cert.ID, err)
+				continue
+			}
+			fmt.Fprintf(out, "Renewed certificate %d, expires %s\n", renewed.ID, renewed.ExpiresOn)
+		}
+
+		fmt.Fprintf(out, "Renew finished: %s\n", result)
+		return result.Err()
+	},
+}
+
+var certificateValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check certificate and key files before uploading them",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		certFile, _ := cmd.Flags().GetString("cert")
+		keyFile, _ := cmd.Flags().GetString("key")
+		intermediateFile, _ := cmd.Flags().GetString("intermediate")
+		if certFile == "" || keyFile == "" {
+			return fmt.Errorf("cert and key are required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		validation, err := client.ValidateCertificate(certFile, keyFile, intermediateFile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out, "Certificate is valid")
+		fmt.Fprintf(out, "Common Name: %s\n", validation.Certificate.CN)
+		if domains, err := pemDNSNames(certFile); err == nil && len(domains) > 0 {
+			fmt.Fprintf(out, "Domains: %s\n", strings.Join(domains, ", "))
+		}
+		fmt.Fprintf(out, "Issuer: %s\n", validation.Certificate.Issuer)
+		fmt.Fprintf(out, "Valid From: %s\n", time.Unix(validation.Certificate.Dates.From, 0).Format(time.RFC3339))
+		fmt.Fprintf(out, "Expires: %s\n", time.Unix(validation.Certificate.Dates.To, 0).Format(time.RFC3339))
+		fmt.Fprintf(out, "Key Matches: %t\n", validation.CertificateKey)
+
+		if !validation.CertificateKey {
+			return fmt.Errorf("certificate key does not match the certificate")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	// Certificate validate flags
+	certificateValidateCmd.Flags().String("cert", "", "Certificate PEM file")
+	certificateValidateCmd.Flags().String("key", "", "Private key PEM file")
+	certificateValidateCmd.Flags().String("intermediate", "", "Intermediate certificate PEM file")
+
+	// Certificate upload flags
+	certificateUploadCmd.Flags().String("name", "", "Nice name for the certificate")
+	certificateUploadCmd.Flags().String("cert", "", "Certificate PEM file, like fullchain.pem")
+	certificateUploadCmd.Flags().String("key", "", "Private key PEM file")
+	certificateUploadCmd.Flags().String("intermediate", "", "Intermediate certificate PEM file")
+
+	// Certificate list flags
+	certificateListCmd.Flags().Bool("all-profiles", false, "List the certificates of all configured profiles")
+
+	// Certificate delete flags
+	certificateDeleteCmd.Flags().Bool("force", false, "Delete the certificate even if hosts still use it")
+	certificateDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	// Certificate renew flags
+	certificateRenewCmd.Flags().Int("id", 0, "ID of the certificate to renew")
+	certificateRenewCmd.Flags().Bool("all", false, "Renew all Let's Encrypt certificates")
+	certificateRenewCmd.Flags().String("expiring-within", "", "With --all, only renew certificates expiring within this time (e.g. 30d or 72h)")
+
+	// Certificate rename flags
+	certificateRenameCmd.Flags().Int("id", 0, "ID of the certificate to rename")
+	certificateRenameCmd.Flags().String("name", "", "New nice name for the certificate")
+
+	certificateCmd.AddCommand(certificateListCmd)
+	certificateCmd.AddCommand(certificateGetCmd)
+	certificateCmd.AddCommand(certificateDeleteCmd)
+	certificateCmd.AddCommand(certificateRenameCmd)
+	certificateCmd.AddCommand(certificateRenewCmd)
+	certificateCmd.AddCommand(certificateValidateCmd)
+	certificateCmd.AddCommand(certificateUploadCmd)
+	rootCmd.AddCommand(certificateCmd)
+}
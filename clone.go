@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone ID",
+	Short: "Create a copy of a proxy host with a new domain",
+	Long: `Create a new proxy host with all settings of an existing one, including its
+advanced config, locations, SSL settings and access list, but serving a new
+domain. Forward and other settings can be overridden with the flags of create.
+
+A certificate of the source host that doesn't cover the new domain is not
+copied, since nginx would serve it with a name mismatch.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		domainName, _ := cmd.Flags().GetString("domain")
+		if domainName == "" {
+			return fmt.Errorf("domain is required")
+		}
+		if err := validateDomainName(domainName); err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		source, err := client.GetProxyHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+		if matches := findProxyHostsByDomain(hosts, domainName); len(matches) > 0 {
+			return fmt.Errorf("domain %s is already served by proxy host %d", domainName, matches[0].ID)
+		}
+
+		host := *source
+		host.ID = 0
+		host.CreatedOn = ""
+		host.ModifiedOn = ""
+		host.Meta = ProxyHostMeta{}
+		host.DomainNames = []string{domainName}
+
+		if host.CertificateID != 0 && !cmd.Flags().Changed("certificate-id") && !cmd.Flags().Changed("certificate") {
+			cert, err := client.GetCertificate(host.CertificateID)
+			if err != nil {
+				return fmt.Errorf("failed to check the certificate of proxy host %d: %w", source.ID, err)
+			}
+			if len(uncoveredDomains(host.DomainNames, cert.DomainNames)) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: certificate %d does not cover %s, the clone is created without SSL\n", cert.ID, domainName)
+				host.CertificateID = 0
+				host.SslForced = false
+				host.HTTP2Support = false
+				host.HSTSEnabled = false
+				host.HSTSSubdomains = false
+			}
+		}
+
+		hostHeaderChanged, err := applyProxyHostFlags(cmd, &host)
+		if err != nil {
+			return err
+		}
+		if err := resolveReferenceFlags(cmd, client, &host); err != nil {
+			return err
+		}
+		if err := applyForwardLoopCheck(cmd, host); err != nil {
+			return err
+		}
+
+		createdHost, err := client.CreateProxyHost(host)
+		if err != nil {
+			return fmt.Errorf("failed to create proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully cloned proxy host %d to new proxy host with ID: %d\n", source.ID, createdHost.ID)
+		fmt.Fprintf(out, "Domain: %v\n", createdHost.DomainNames)
+		fmt.Fprintf(out, "Forward: %s://%s:%d\n", createdHost.ForwardScheme, createdHost.ForwardHost, createdHost.ForwardPort)
+		fmt.Fprintf(out, "Enabled: %t\n", createdHost.Enabled)
+		if hostHeaderChanged {
+			fmt.Fprintf(out, "Host Header: %s\n", hostHeaderSetting(createdHost.AdvancedConfig))
+		}
+		fmt.Fprintf(out, "SSL: %s\n", sslStatus(*createdHost))
+
+		return nil
+	},
+}
+
+func init() {
+	cloneCmd.Flags().String("domain", "", "Domain name of the new proxy host")
+	cloneCmd.Flags().String("forward-host", "", "Override the forward host")
+	cloneCmd.Flags().Int("forward-port", 0, "Override the forward port")
+	cloneCmd.Flags().String("forward-scheme", "", "Override the forward scheme (http or https)")
+	addSSLFlags(cloneCmd)
+	addHostOptionFlags(cloneCmd)
+	addAccessListFlags(cloneCmd)
+	addLocationFlags(cloneCmd)
+	addAdvancedConfigFlag(cloneCmd)
+	addHostHeaderFlags(cloneCmd)
+	cloneCmd.Flags().Bool("force", false, "Create the clone even if safety checks fail")
+
+	rootCmd.AddCommand(cloneCmd)
+}
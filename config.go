@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	configFile  string
+	profileName string
+)
+
+// Profile holds the connection settings of one Nginx Proxy Manager instance
+type Profile struct {
+	APIURL   string `json:"api_url"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// Config is the configuration file of the CLI
+type Config struct {
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// configPath returns the path of the configuration file
+func configPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "nginxproxymanager-cli", "config.json"), nil
+}
+
+// loadConfig reads the configuration file. A missing file is an empty configuration.
+func loadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// profile returns the named profile from the configuration file
+func (c *Config) profile(name string) (Profile, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found, known profiles: %v", name, c.profileNames())
+	}
+	if profile.APIURL == "" {
+		return Profile{}, fmt.Errorf("profile %q has no api_url", name)
+	}
+	return profile, nil
+}
+
+// profileNames returns the names of all profiles in sorted order
+func (c *Config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile uses the settings of the --profile profile for all connection
+// flags that weren't given explicitly on the command line
+func applyProfile(cmd *cobra.Command) error {
+	if profileName == "" {
+		return nil
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	profile, err := config.profile(profileName)
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if !flags.Changed("api-url") {
+		apiURL = profile.APIURL
+	}
+	if !flags.Changed("username") && profile.Username != "" {
+		username = profile.Username
+	}
+	if !flags.Changed("password") && profile.Password != "" {
+		password = profile.Password
+		passwordSource = "profile"
+	}
+	if !flags.Changed("token") && profile.Token != "" {
+		token = profile.Token
+	}
+
+	return nil
+}
+
+// newProfileClient creates an API client for the named profile and authenticates it
+func newProfileClient(config *Config, name string) (*APIClient, error) {
+	profile, err := config.profile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newClient(profile.APIURL, profile.Token)
+	if err := client.Authenticate(profile.Username, profile.Password); err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	return client, nil
+}
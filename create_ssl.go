@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// hostSSLSettings are the settings of a proxy host that only work with a
+// certificate
+type hostSSLSettings struct {
+	SslForced      bool
+	HSTSEnabled    bool
+	HSTSSubdomains bool
+}
+
+// addCreateSSLFlags registers the flags requesting a certificate for a new host
+func addCreateSSLFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("ssl", false, "Request a Let's Encrypt certificate for the domains, attach it and force SSL")
+	cmd.Flags().String("letsencrypt-email", "", "Email for the Let's Encrypt account with --ssl (default: email of the current user)")
+	cmd.Flags().Bool("agree-tos", false, "Accept the Let's Encrypt terms of service, required with --ssl")
+	cmd.Flags().Duration("ssl-timeout", 5*time.Minute, "Maximum time to wait for the certificate with --ssl")
+	cmd.MarkFlagsMutuallyExclusive("ssl", "certificate-id")
+	cmd.MarkFlagsMutuallyExclusive("ssl", "certificate")
+}
+
+// validateCreateSSLFlags checks the --ssl flags against the domains of the
+// host to create
+func validateCreateSSLFlags(cmd *cobra.Command, domains []string) error {
+	flags := cmd.Flags()
+	if ssl, _ := flags.GetBool("ssl"); !ssl {
+		if flags.Changed("letsencrypt-email") || flags.Changed("agree-tos") || flags.Changed("ssl-timeout") {
+			return fmt.Errorf("--letsencrypt-email, --agree-tos and --ssl-timeout require --ssl")
+		}
+		return nil
+	}
+
+	if agreeTOS, _ := flags.GetBool("agree-tos"); !agreeTOS {
+		return fmt.Errorf("the Let's Encrypt terms of service must be accepted with --agree-tos")
+	}
+	for _, domain := range domains {
+		if strings.HasPrefix(domain, "*.") {
+			return fmt.Errorf("wildcard domain %s needs the DNS challenge, request the certificate with cert create and pass it with --certificate", domain)
+		}
+	}
+	return nil
+}
+
+// takeSSLSettings clears the settings that need a certificate from a host
+// that is created before its certificate exists, and returns them
+func takeSSLSettings(host *ProxyHost) hostSSLSettings {
+	settings := hostSSLSettings{
+		SslForced:      host.SslForced,
+		HSTSEnabled:    host.HSTSEnabled,
+		HSTSSubdomains: host.HSTSSubdomains,
+	}
+	host.SslForced = false
+	host.HSTSEnabled = false
+	host.HSTSSubdomains = false
+	return settings
+}
+
+// enableHostSSL requests a Let's Encrypt certificate for the domains of a new
+// proxy host, attaches it and applies the SSL settings taken before creating
+// the host. The host keeps serving plain HTTP if anything fails.
+func enableHostSSL(client *APIClient, host ProxyHost, settings hostSSLSettings, email string, timeout time.Duration) (*ProxyHost, error) {
+	fmt.Fprintf(out, "Requesting Let's Encrypt certificate for %s...\n", strings.Join(host.DomainNames, ", "))
+	cert, err := client.IssueCertificate(Certificate{
+		Provider:    "letsencrypt",
+		NiceName:    host.DomainNames[0],
+		DomainNames: host.DomainNames,
+		Meta: CertificateMeta{
+			LetsEncryptAgree: true,
+			LetsEncryptEmail: email,
+		},
+	}, timeout)
+	var requestErr *CertificateRequestError
+	if errors.As(err, &requestErr) {
+		fmt.Fprintln(os.Stderr, "NPM reported:")
+		fmt.Fprintln(os.Stderr, requestErr.Output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("proxy host %d was created without SSL: %w", host.ID, err)
+	}
+	fmt.Fprintf(out, "Certificate: %d (expires %s)\n", cert.ID, cert.ExpiresOn)
+
+	host.CertificateID = cert.ID
+	host.SslForced = settings.SslForced
+	host.HSTSEnabled = settings.HSTSEnabled
+	host.HSTSSubdomains = settings.HSTSSubdomains
+
+	updatedHost, err := client.UpdateProxyHost(host.ID, host)
+	if err != nil {
+		return nil, fmt.Errorf("certificate %d was issued, but attaching it to proxy host %d failed: %w", cert.ID, host.ID, err)
+	}
+	return updatedHost, nil
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name of the CLI's entries in the system keyring
+const keyringService = "nginxproxymanager-cli"
+
+// credentialStore is the store selected with --credential-store, resolved
+// to "file" when the keyring isn't available
+var credentialStore string
+
+// passwordSource is where the password in use was read from: "keyring" or
+// "file" for the credential stores, "profile" for the config file, and empty
+// for flags and the environment
+var passwordSource string
+
+// storedCredentials are the credentials saved by login for one API URL
+type storedCredentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// keyringAvailable checks that a system keyring can be used. Looking up an
+// entry that doesn't exist only fails with ErrNotFound when a keyring is there to ask.
+func keyringAvailable() error {
+	if _, err := keyring.Get(keyringService, "availability-check"); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// resolveCredentialStore checks the --credential-store value. When the system
+// keyring is requested but not available, the file store is used instead.
+func resolveCredentialStore(name string) (string, error) {
+	switch name {
+	case "file":
+		return "file", nil
+	case "keyring":
+		if err := keyringAvailable(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: system keyring not available (%v), using the credentials file\n", err)
+			return "file", nil
+		}
+		return "keyring", nil
+	default:
+		return "", fmt.Errorf("invalid credential store %q, expected file or keyring", name)
+	}
+}
+
+// credentialsPath returns the path of the credentials file, next to the config file
+func credentialsPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "credentials.json"), nil
+}
+
+// readCredentialsFile reads all credentials of the file store, keyed by API URL
+func readCredentialsFile() (map[string]storedCredentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]storedCredentials{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var all map[string]storedCredentials
+	if err := json.Unmarshal(content, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", path, err)
+	}
+	if all == nil {
+		all = map[string]storedCredentials{}
+	}
+	return all, nil
+}
+
+// writeCredentialsFile replaces the file store, readable only by the current user
+func writeCredentialsFile(all map[string]storedCredentials) error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	if err := os.WriteFile(path, jsonData, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+// loadCredentials returns the stored credentials for an API URL, or nil when there are none
+func loadCredentials(store, url string) (*storedCredentials, error) {
+	if store == "keyring" {
+		secret, err := keyring.Get(keyringService, url)
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from keyring: %w", err)
+		}
+
+		var creds storedCredentials
+		if err := json.Unmarshal([]byte(secret), &creds); err != nil {
+			return nil, fmt.Errorf("failed to parse keyring entry: %w", err)
+		}
+		return &creds, nil
+	}
+
+	all, err := readCredentialsFile()
+	if err != nil {
+		return nil, err
+	}
+	if creds, ok := all[url]; ok {
+		return &creds, nil
+	}
+	return nil, nil
+}
+
+// saveCredentials stores the credentials for an API URL
+func saveCredentials(store, url string, creds storedCredentials) error {
+	if store == "keyring" {
+		secret, err := json.Marshal(creds)
+		if err != nil {
+			return fmt.Errorf("failed to marshal credentials: %w", err)
+		}
+		if err := keyring.Set(keyringService, url, string(secret)); err != nil {
+			return fmt.Errorf("failed to write to keyring: %w", err)
+		}
+		return nil
+	}
+
+	all, err := readCredentialsFile()
+	if err != nil {
+		return err
+	}
+	all[url] = creds
+	return writeCredentialsFile(all)
+}
+
+// deleteCredentials removes the credentials for an API URL and reports whether there were any
+func deleteCredentials(store, url string) (bool, error) {
+	if store == "keyring" {
+		err := keyring.Delete(keyringService, url)
+		if errors.Is(err, keyring.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to delete from keyring: %w", err)
+		}
+		return true, nil
+	}
+
+	all, err := readCredentialsFile()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := all[url]; !ok {
+		return false, nil
+	}
+	delete(all, url)
+	return true, writeCredentialsFile(all)
+}
+
+// applyStoredCredentials uses the credentials saved by login for the API URL
+// when no credentials were given by flags, environment or profile. Both
+// stores are checked, keyring first, as --credential-store is only given to
+// login, like logout clears both.
+func applyStoredCredentials() error {
+	if username != "" || password != "" || token != "" {
+		return nil
+	}
+
+	for _, store := range []string{"keyring", "file"} {
+		if store == "keyring" && keyringAvailable() != nil {
+			continue
+		}
+
+		creds, err := loadCredentials(store, apiURL)
+		if err != nil {
+			return err
+		}
+		if creds != nil {
+			username = creds.Username
+			password = creds.Password
+			token = creds.Token
+			if password != "" {
+				passwordSource = store
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Check credentials and store them for later commands",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		if token == "" {
+			if username == "" {
+				return fmt.Errorf("username is required")
+			}
+			if password == "" {
+				if password, err = readPassword("Password: "); err != nil {
+					return err
+				}
+			}
+		}
+
+		client := newClient(apiURL, token)
+		if err := client.Authenticate(username, password); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		// A pre-issued token is stored on its own, the credentials are enough otherwise
+		creds := storedCredentials{Username: username, Password: password}
+		if token != "" {
+			creds = storedCredentials{Token: token}
+		}
+		if err := saveCredentials(credentialStore, apiURL, creds); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Logged in to %s\n", apiURL)
+		fmt.Fprintf(out, "Credentials stored in: %s\n", credentialStore)
+		return nil
+	},
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored credentials for the API URL",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Clear both stores, credentials may have been saved with either
+		removed := false
+		for _, store := range []string{"keyring", "file"} {
+			if store == "keyring" && keyringAvailable() != nil {
+				continue
+			}
+
+			deleted, err := deleteCredentials(store, apiURL)
+			if err != nil {
+				return err
+			}
+			if deleted {
+				removed = true
+				fmt.Fprintf(out, "Removed stored credentials for %s from %s\n", apiURL, store)
+			}
+		}
+
+		if !removed {
+			fmt.Fprintf(out, "No stored credentials for %s\n", apiURL)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+}
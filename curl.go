@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	showCurl    bool
+	showSecrets bool
+)
+
+// secretFields are the JSON request fields redacted from --show-curl output
+var secretFields = map[string]bool{
+	"password": true,
+	"secret":   true,
+	"current":  true,
+	"token":    true,
+	// Certificate meta, holding the API keys of a DNS provider
+	"dns_provider_credentials": true,
+}
+
+// redactBody replaces the values of secret fields in a JSON object body,
+// including those of nested objects and of objects in arrays, like the basic
+// auth users of an access list
+func redactBody(payload []byte) []byte {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(payload, &elements); err == nil {
+		return redactArray(payload, elements)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload
+	}
+
+	redacted := false
+	for name, value := range fields {
+		if secretFields[name] {
+			fields[name] = json.RawMessage(`"REDACTED"`)
+			redacted = true
+			continue
+		}
+		if nested := redactBody(value); !bytes.Equal(nested, value) {
+			fields[name] = nested
+			redacted = true
+		}
+	}
+	if !redacted {
+		return payload
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return data
+}
+
+// redactArray redacts the elements of a JSON array
+func redactArray(payload []byte, elements []json.RawMessage) []byte {
+	redacted := false
+	for i, element := range elements {
+		if nested := redactBody(element); !bytes.Equal(nested, element) {
+			elements[i] = nested
+			redacted = true
+		}
+	}
+	if !redacted {
+		return payload
+	}
+
+	data, err := json.Marshal(elements)
+	if err != nil {
+		return payload
+	}
+	return data
+}
+
+// curlCommand returns a curl command line equivalent to the request
+func curlCommand(req *http.Request, payload []byte) string {
+	args := []string{"curl", "-X", req.Method}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			if name == "Authorization" && !showSecrets {
+				value = "Bearer REDACTED"
+			}
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	if len(payload) > 0 {
+		switch {
+		case !utf8.Valid(payload):
+			args = append(args, "--data-binary", shellQuote(fmt.Sprintf("<%d bytes of binary data>", len(payload))))
+		case showSecrets:
+			args = append(args, "--data-raw", shellQuote(string(payload)))
+		default:
+			args = append(args, "--data-raw", shellQuote(string(redactBody(payload))))
+		}
+	}
+
+	args = append(args, shellQuote(req.URL.String()))
+	return strings.Join(args, " ")
+}
+
+// printCurl writes the curl equivalent of a request to stderr when --show-curl is set
+func printCurl(req *http.Request, payload []byte) {
+	if showCurl {
+		fmt.Fprintln(os.Stderr, curlCommand(req, payload))
+	}
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// DeadHost represents a 404 host, which answers every request with 404 Not Found
+type DeadHost struct {
+	ID             int           `json:"id"`
+	DomainNames    []string      `json:"domain_names"`
+	CertificateID  int           `json:"certificate_id"`
+	SslForced      bool          `json:"ssl_forced"`
+	HSTSEnabled    bool          `json:"hsts_enabled"`
+	HSTSSubdomains bool          `json:"hsts_subdomains"`
+	HTTP2Support   bool          `json:"http2_support"`
+	AdvancedConfig string        `json:"advanced_config"`
+	Enabled        bool          `json:"enabled"`
+	CreatedOn      string        `json:"created_on"`
+	ModifiedOn     string        `json:"modified_on"`
+	Meta           ProxyHostMeta `json:"meta"`
+}
+
+// tls returns the SSL settings of the 404 host
+func (h DeadHost) tls() hostTLS {
+	return hostTLS{
+		CertificateID:  h.CertificateID,
+		SslForced:      h.SslForced,
+		HTTP2Support:   h.HTTP2Support,
+		HSTSEnabled:    h.HSTSEnabled,
+		HSTSSubdomains: h.HSTSSubdomains,
+	}
+}
+
+// setTLS replaces the SSL settings of the 404 host
+func (h *DeadHost) setTLS(t hostTLS) {
+	h.CertificateID = t.CertificateID
+	h.SslForced = t.SslForced
+	h.HTTP2Support = t.HTTP2Support
+	h.HSTSEnabled = t.HSTSEnabled
+	h.HSTSSubdomains = t.HSTSSubdomains
+}
+
+// GetDeadHost fetches a single 404 host by ID
+func (c *APIClient) GetDeadHost(id int) (*DeadHost, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/dead-hosts/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("404 host %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get 404 host, status: %d", resp.StatusCode)
+	}
+
+	var host DeadHost
+	if err := decodeJSON(resp.Body, &host); err != nil {
+		return nil, fmt.Errorf("failed to decode 404 host: %w", err)
+	}
+
+	return &host, nil
+}
+
+// ListDeadHosts lists all 404 hosts
+func (c *APIClient) ListDeadHosts() ([]DeadHost, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/dead-hosts", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list 404 hosts, status: %d", resp.StatusCode)
+	}
+
+	var hosts []DeadHost
+	if err := decodeJSON(resp.Body, &hosts); err != nil {
+		return nil, fmt.Errorf("failed to decode 404 hosts: %w", err)
+	}
+
+	return hosts, nil
+}
+
+// UpdateDeadHost updates an existing 404 host
+func (c *APIClient) UpdateDeadHost(id int, host DeadHost) (*DeadHost, error) {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal 404 host: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/nginx/dead-hosts/%d", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update 404 host, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var updatedHost DeadHost
+	if err := decodeJSON(resp.Body, &updatedHost); err != nil {
+		return nil, fmt.Errorf("failed to decode updated 404 host: %w", err)
+	}
+
+	return &updatedHost, nil
+}
+
+// CreateDeadHost creates a new 404 host
+func (c *APIClient) CreateDeadHost(host DeadHost) (*DeadHost, error) {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal 404 host: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", "/nginx/dead-hosts", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create 404 host, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var createdHost DeadHost
+	if err := decodeJSON(resp.Body, &createdHost); err != nil {
+		return nil, fmt.Errorf("failed to decode created 404 host: %w", err)
+	}
+
+	return &createdHost, nil
+}
+
+// DeleteDeadHost deletes a 404 host by ID
+func (c *APIClient) DeleteDeadHost(id int) error {
+	resp, err := c.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/nginx/dead-hosts/%d", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("404 host %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete 404 host, status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SetDeadHostEnabled enables or disables a 404 host without changing its configuration
+func (c *APIClient) SetDeadHostEnabled(id int, enabled bool) error {
+	return c.setEnabled("dead-hosts", "404 host", id, enabled)
+}
+
+// printDeadHost prints the details of a 404 host
+func printDeadHost(host DeadHost) {
+	fmt.Fprintf(out, "ID: %d\n", host.ID)
+	fmt.Fprintf(out, "Domains: %s\n", strings.Join(host.DomainNames, ", "))
+	fmt.Fprintf(out, "SSL: %s\n", host.tls().status())
+	if host.CertificateID != 0 {
+		fmt.Fprintf(out, "Certificate ID: %d\n", host.CertificateID)
+	}
+	fmt.Fprintf(out, "HTTP/2: %t\n", host.HTTP2Support)
+	fmt.Fprintf(out, "HSTS: %t\n", host.HSTSEnabled)
+	fmt.Fprintf(out, "Enabled: %t\n", host.Enabled)
+}
+
+var deadHostCmd = &cobra.Command{
+	Use:   "dead-host",
+	Short: "Manage 404 hosts",
+	Long: `Manage 404 hosts, which answer every request for their domains with 404 Not
+Found. They keep parked domains from falling through to another host.`,
+}
+
+var deadHostListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all 404 hosts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListDeadHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list 404 hosts: %w", err)
+		}
+
+		if output == "json" {
+			return writeJSON(append([]DeadHost{}, hosts...))
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tDOMAINS\tSSL\tCERTIFICATE\tENABLED")
+		for _, host := range hosts {
+			certificate := "-"
+			if host.CertificateID != 0 {
+				certificate = fmt.Sprint(host.CertificateID)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\n", host.ID, strings.Join(host.DomainNames, ","), host.tls().status(), certificate, host.Enabled)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var deadHostGetCmd = &cobra.Command{
+	Use:   "get ID",
+	Short: "Show a 404 host",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetDeadHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(host)
+		}
+		printDeadHost(*host)
+		return nil
+	},
+}
+
+var deadHostCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a 404 host",
+	Long: `Create a 404 host for parked domains. With a certificate, HTTPS requests are
+answered with 404 too instead of a certificate error.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
+		disabled, _ := cmd.Flags().GetBool("disabled")
+
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
+		}
+		if len(domains) == 0 {
+			return fmt.Errorf("domain is required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host := DeadHost{
+			DomainNames: domains,
+			Enabled:     !disabled,
+		}
+
+		tls := host.tls()
+		if err := applyTLSFlags(cmd, client, domains, &tls); err != nil {
+			return err
+		}
+		host.setTLS(tls)
+
+		if err := applyDuplicateDomainCheck(cmd, client, domains, false); err != nil {
+			return err
+		}
+
+		createdHost, err := client.CreateDeadHost(host)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully created 404 host with ID: %d\n", createdHost.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(createdHost.DomainNames, ", "))
+		fmt.Fprintf(out, "SSL: %s\n", createdHost.tls().status())
+		return nil
+	},
+}
+
+var deadHostUpdateCmd = &cobra.Command{
+	Use:   "update ID",
+	Short: "Update a 404 host",
+	Long: `Update a 404 host. Only the settings given as flags are changed, so a
+certificate is attached with --certificate and removed with --certificate-id 0
+--no-ssl-redirect.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
+
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("domain") && len(domains) == 0 {
+			return fmt.Errorf("domain cannot be empty")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetDeadHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		if len(domains) > 0 {
+			// Only the new domains can clash with other hosts
+			var added []string
+			for _, domain := range domains {
+				if hostDomainIndex(ProxyHost{DomainNames: host.DomainNames}, domain) < 0 {
+					added = append(added, domain)
+				}
+			}
+			if err := applyDuplicateDomainCheck(cmd, client, added, false); err != nil {
+				return err
+			}
+			host.DomainNames = domains
+		}
+
+		if tlsFlagsChanged(cmd) {
+			tls := host.tls()
+			if err := applyTLSFlags(cmd, client, host.DomainNames, &tls); err != nil {
+				return err
+			}
+			host.setTLS(tls)
+		}
+
+		updatedHost, err := client.UpdateDeadHost(host.ID, *host)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully updated 404 host with ID: %d\n", updatedHost.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(updatedHost.DomainNames, ", "))
+		fmt.Fprintf(out, "SSL: %s\n", updatedHost.tls().status())
+		return nil
+	},
+}
+
+var deadHostDeleteCmd = &cobra.Command{
+	Use:          "delete ID...",
+	Short:        "Delete 404 hosts",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		yes, _ := cmd.Flags().GetBool("yes")
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Delete %d 404 hosts?", len(ids))) {
+			return fmt.Errorf("aborted")
+		}
+
+		var result BatchResult
+		for _, id := range ids {
+			err := client.DeleteDeadHost(id)
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to delete 404 host %d: %v\n", id, err)
+				continue
+			}
+			fmt.Fprintf(out, "Deleted 404 host %d\n", id)
+		}
+
+		return result.Err()
+	},
+}
+
+var deadHostEnableCmd = &cobra.Command{
+	Use:          "enable ID...",
+	Short:        "Enable 404 hosts",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleHosts("404 host", (*APIClient).SetDeadHostEnabled, true),
+}
+
+var deadHostDisableCmd = &cobra.Command{
+	Use:          "disable ID...",
+	Short:        "Disable 404 hosts",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleHosts("404 host", (*APIClient).SetDeadHostEnabled, false),
+}
+
+// addDeadHostFlags registers the flags shared by dead-host create and update
+func addDeadHostFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("domain", nil, "Domain names of the 404 host (repeatable or comma separated)")
+	cmd.Flags().Bool("force", false, "Allow domains that are already served by another host")
+	addTLSFlags(cmd)
+}
+
+func init() {
+	addDeadHostFlags(deadHostCreateCmd)
+	deadHostCreateCmd.Flags().Bool("disabled", false, "Create the 404 host disabled")
+	addDeadHostFlags(deadHostUpdateCmd)
+
+	deadHostDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	deadHostCmd.AddCommand(deadHostListCmd)
+	deadHostCmd.AddCommand(deadHostGetCmd)
+	deadHostCmd.AddCommand(deadHostCreateCmd)
+	deadHostCmd.AddCommand(deadHostUpdateCmd)
+	deadHostCmd.AddCommand(deadHostDeleteCmd)
+	deadHostCmd.AddCommand(deadHostEnableCmd)
+	deadHostCmd.AddCommand(deadHostDisableCmd)
+	rootCmd.AddCommand(deadHostCmd)
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// matchesDomain reports whether a domain name pattern, which may be a
+// wildcard like *.example.com, matches a concrete domain. As in nginx and
+// X.509, a wildcard only covers a single label, so *.example.com matches
+// api.example.com but neither example.com nor a.b.example.com.
+func matchesDomain(pattern, domain string) bool {
+	pattern = strings.ToLower(pattern)
+	domain = strings.ToLower(domain)
+
+	if pattern == domain {
+		return true
+	}
+
+	suffix, ok := strings.CutPrefix(pattern, "*.")
+	if !ok {
+		return false
+	}
+	label, rest, found := strings.Cut(domain, ".")
+	return found && label != "" && rest == suffix
+}
+
+// uncoveredDomains returns the domains not matched by any of the patterns
+func uncoveredDomains(domains, patterns []string) []string {
+	var uncovered []string
+	for _, domain := range domains {
+		covered := false
+		for _, pattern := range patterns {
+			if matchesDomain(pattern, domain) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, domain)
+		}
+	}
+	return uncovered
+}
+
+// lookupProxyHostsByDomain returns the hosts serving a domain. With
+// matchWildcards, hosts with a wildcard entry covering the domain are
+// candidates too, so *.example.com is found for api.example.com.
+func lookupProxyHostsByDomain(hosts []ProxyHost, domain string, matchWildcards bool) []ProxyHost {
+	if !matchWildcards {
+		return findProxyHostsByDomain(hosts, domain)
+	}
+
+	var matches []ProxyHost
+	for _, host := range hosts {
+		for _, name := range host.DomainNames {
+			if matchesDomain(name, domain) {
+				matches = append(matches, host)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// selectProxyHostByDomain picks the single host serving a domain. When
+// several hosts match, id must name one of them.
+func selectProxyHostByDomain(hosts []ProxyHost, domain string, matchWildcards bool, id int) (*ProxyHost, error) {
+	candidates := lookupProxyHostsByDomain(hosts, domain, matchWildcards)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no proxy host found for domain %s", domain)
+	}
+
+	if id != 0 {
+		for _, host := range candidates {
+			if host.ID == id {
+				return &host, nil
+			}
+		}
+		return nil, fmt.Errorf("proxy host %d does not serve domain %s", id, domain)
+	}
+
+	if len(candidates) > 1 {
+		var lines []string
+		for _, host := range candidates {
+			lines = append(lines, fmt.Sprintf("  %d %v", host.ID, host.DomainNames))
+		}
+		return nil, fmt.Errorf("domain %s matches %d proxy hosts, choose one with --id:\n%s",
+			domain, len(candidates), strings.Join(lines, "\n"))
+	}
+
+	return &candidates[0], nil
+}
+
+// validateDomainName checks that a domain name, optionally with a leading
+// wildcard label, is syntactically valid
+func validateDomainName(domain string) error {
+	name := strings.TrimPrefix(domain, "*.")
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("invalid domain name %q", domain)
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("invalid domain name %q", domain)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return fmt.Errorf("invalid domain name %q", domain)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseDomainList validates the domains given with a repeatable or comma
+// separated --domain flag and drops duplicates
+func parseDomainList(values []string) ([]string, error) {
+	var domains []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		domain := strings.TrimSpace(value)
+		if domain == "" || seen[strings.ToLower(domain)] {
+			continue
+		}
+		if err := validateDomainName(domain); err != nil {
+			return nil, err
+		}
+		seen[strings.ToLower(domain)] = true
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// hostDomainIndex returns the index of domain in the domain names of a host, or -1
+func hostDomainIndex(host ProxyHost, domain string) int {
+	for i, name := range host.DomainNames {
+		if strings.EqualFold(name, domain) {
+			return i
+		}
+	}
+	return -1
+}
+
+var domainCmd = &cobra.Command{
+	Use:   "domain",
+	Short: "Add or remove domain names of a proxy host",
+}
+
+var domainAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a domain name to a proxy host",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		hostID, _ := cmd.Flags().GetInt("host-id")
+		domain, _ := cmd.Flags().GetString("domain")
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if hostID == 0 || domain == "" {
+			return fmt.Errorf("host-id and domain are required")
+		}
+		if err := validateDomainName(domain); err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(hostID)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy host: %w", err)
+		}
+
+		if hostDomainIndex(*host, domain) >= 0 {
+			fmt.Fprintf(os.Stderr, "Warning: proxy host %d already has domain %s, nothing to do\n", hostID, domain)
+			return nil
+		}
+
+		host.DomainNames = append(host.DomainNames, domain)
+
+		updatedHost, err := client.UpdateProxyHost(hostID, *host)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Added %s to proxy host %d\n", domain, updatedHost.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(updatedHost.DomainNames, ", "))
+
+		return nil
+	},
+}
+
+var domainRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a domain name from a proxy host",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		hostID, _ := cmd.Flags().GetInt("host-id")
+		domain, _ := cmd.Flags().GetString("domain")
+		domain = strings.TrimSpace(domain)
+		if hostID == 0 || domain == "" {
+			return fmt.Errorf("host-id and domain are required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(hostID)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy host: %w", err)
+		}
+
+		index := hostDomainIndex(*host, domain)
+		if index < 0 {
+			return fmt.Errorf("proxy host %d does not have domain %s", hostID, domain)
+		}
+		if len(host.DomainNames) == 1 {
+			return fmt.Errorf("refusing to remove %s, it is the last domain of proxy host %d", domain, hostID)
+		}
+
+		host.DomainNames = append(host.DomainNames[:index], host.DomainNames[index+1:]...)
+
+		updatedHost, err := client.UpdateProxyHost(hostID, *host)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Removed %s from proxy host %d\n", domain, updatedHost.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(updatedHost.DomainNames, ", "))
+
+		return nil
+	},
+}
+
+func init() {
+	domainAddCmd.Flags().Int("host-id", 0, "ID of the proxy host")
+	domainAddCmd.Flags().String("domain", "", "Domain name to add")
+
+	domainRemoveCmd.Flags().Int("host-id", 0, "ID of the proxy host")
+	domainRemoveCmd.Flags().String("domain", "", "Domain name to remove")
+
+	domainCmd.AddCommand(domainAddCmd)
+	domainCmd.AddCommand(domainRemoveCmd)
+	rootCmd.AddCommand(domainCmd)
+}
+
+// domainsInUse returns the owners serving any of the domains, compared case-insensitively
+func domainsInUse(owners []domainOwner, domains []string) []domainOwner {
+	var used []domainOwner
+	for _, owner := range owners {
+		for _, domain := range domains {
+			if strings.EqualFold(owner.Domain, domain) {
+				used = append(used, owner)
+				break
+			}
+		}
+	}
+	return used
+}
+
+// applyDuplicateDomainCheck refuses domains already served by a proxy,
+// redirection or 404 host unless --force was given, in which case it only
+// warns. With skipProxyHosts, proxy hosts are left to the caller, like create
+// --replace does.
+func applyDuplicateDomainCheck(cmd *cobra.Command, client *APIClient, domains []string, skipProxyHosts bool) error {
+	owners, err := listDomainOwners(client)
+	if err != nil {
+		return fmt.Errorf("failed to check for duplicate domains: %w", err)
+	}
+
+	var lines []string
+	for _, owner := range domainsInUse(owners, domains) {
+		if skipProxyHosts && owner.Type == "proxy host" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s is already served by %s", owner.Domain, owner))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		for _, line := range lines {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", line)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s. Use --force if this is intended", strings.Join(lines, "; "))
+}
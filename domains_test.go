@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var lookupTestHosts = []ProxyHost{
+	{ID: 1, DomainNames: []string{"api.example.com"}},
+	{ID: 2, DomainNames: []string{"*.example.com"}},
+	{ID: 3, DomainNames: []string{"www.example.org", "example.org"}},
+	{ID: 4, DomainNames: []string{"API.example.com"}},
+}
+
+func hostIDs(hosts []ProxyHost) []int {
+	var ids []int
+	for _, host := range hosts {
+		ids = append(ids, host.ID)
+	}
+	return ids
+}
+
+func TestLookupProxyHostsByDomain(t *testing.T) {
+	tests := []struct {
+		name           string
+		domain         string
+		matchWildcards bool
+		want           []int
+	}{
+		{"exact", "www.example.org", false, []int{3}},
+		{"exact ignores case", "Example.ORG", false, []int{3}},
+		{"exact skips wildcards", "shop.example.com", false, nil},
+		{"wildcard", "shop.example.com", true, []int{2}},
+		{"wildcard covers a single label only", "a.shop.example.com", true, nil},
+		{"wildcard does not cover the bare domain", "example.com", true, nil},
+		{"multiple exact", "api.example.com", false, []int{1, 4}},
+		{"multiple with wildcard", "api.example.com", true, []int{1, 2, 4}},
+		{"no match", "example.net", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hostIDs(lookupProxyHostsByDomain(lookupTestHosts, tt.domain, tt.matchWildcards))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookupProxyHostsByDomain(%q, %t) = %v, want %v", tt.domain, tt.matchWildcards, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectProxyHostByDomain(t *testing.T) {
+	tests := []struct {
+		name           string
+		domain         string
+		matchWildcards bool
+		id             int
+		want           int
+		wantErr        string
+	}{
+		{"exact", "example.org", false, 0, 3, ""},
+		{"wildcard", "shop.example.com", true, 0, 2, ""},
+		{"wildcard not asked for", "shop.example.com", false, 0, 0, "no proxy host found for domain shop.example.com"},
+		{"ambiguous", "api.example.com", true, 0, 0, "domain api.example.com matches 3 proxy hosts, choose one with --id"},
+		{"ambiguous resolved by id", "api.example.com", true, 2, 2, ""},
+		{"id not among the matches", "api.example.com", true, 3, 0, "proxy host 3 does not serve domain api.example.com"},
+		{"no match", "example.net", true, 0, 0, "no proxy host found for domain example.net"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, err := selectProxyHostByDomain(lookupTestHosts, tt.domain, tt.matchWildcards, tt.id)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if host.ID != tt.want {
+				t.Errorf("got proxy host %d, want %d", host.ID, tt.want)
+			}
+		})
+	}
+}
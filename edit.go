@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// readOnlyFields are proxy host fields maintained by NPM that can't be edited
+var readOnlyFields = []string{"id", "created_on", "modified_on", "meta"}
+
+// editableFields returns the proxy host as JSON fields without the read-only ones
+func editableFields(host ProxyHost) (map[string]json.RawMessage, error) {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal proxy host: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(jsonData, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy host: %w", err)
+	}
+	for _, field := range readOnlyFields {
+		delete(fields, field)
+	}
+	return fields, nil
+}
+
+// checkEditableFields rejects unknown and read-only proxy host fields
+func checkEditableFields(fields map[string]json.RawMessage) error {
+	known := proxyHostFields()
+	for _, field := range readOnlyFields {
+		delete(known, field)
+	}
+
+	var invalid []string
+	for field := range fields {
+		if !known[field] {
+			invalid = append(invalid, field)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("unknown or read-only fields: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// validateProxyHost checks a proxy host for values the API would reject or
+// that can't work
+func validateProxyHost(host ProxyHost) error {
+	if len(host.DomainNames) == 0 {
+		return fmt.Errorf("domain_names must not be empty")
+	}
+	for _, domain := range host.DomainNames {
+		if err := validateDomainName(domain); err != nil {
+			return err
+		}
+	}
+	if host.ForwardScheme != "http" && host.ForwardScheme != "https" {
+		return fmt.Errorf("forward_scheme must be http or https, not %q", host.ForwardScheme)
+	}
+	if host.ForwardHost == "" {
+		return fmt.Errorf("forward_host must not be empty")
+	}
+	if host.ForwardPort < 1 || host.ForwardPort > 65535 {
+		return fmt.Errorf("forward_port %d is out of range 1-65535", host.ForwardPort)
+	}
+	if host.SslForced && host.CertificateID == 0 {
+		return fmt.Errorf("ssl_forced requires a certificate_id")
+	}
+	if host.HSTSSubdomains && !host.HSTSEnabled {
+		return fmt.Errorf("hsts_subdomains requires hsts_enabled")
+	}
+	for i, location := range host.Locations {
+		if !strings.HasPrefix(location.Path, "/") || location.ForwardHost == "" || location.ForwardPort == 0 {
+			return fmt.Errorf("location %d needs a path starting with /, forward_host and forward_port", i)
+		}
+	}
+	return nil
+}
+
+// runEditor opens a file in $VISUAL or $EDITOR, falling back to vi
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor may come with arguments, like "code --wait"
+	args := append(strings.Fields(editor), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	return nil
+}
+
+// parseEditedHost applies the edited JSON to the host and validates the result
+func parseEditedHost(host ProxyHost, content []byte) (ProxyHost, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return host, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := checkEditableFields(fields); err != nil {
+		return host, err
+	}
+
+	edited, err := mergeProxyHostFields(host, fields)
+	if err != nil {
+		return host, err
+	}
+	return edited, validateProxyHost(edited)
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit ID",
+	Short: "Edit a proxy host as JSON in your editor",
+	Long: `Open the JSON of a proxy host in $VISUAL or $EDITOR (vi if neither is set)
+and save the changes back when the editor exits.
+
+All editable fields are included, so this also covers settings the CLI has no
+flags for. The result is validated before it is sent; on errors the file can be
+edited again. Saving the file unchanged cancels the edit.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		fields, err := editableFields(*host)
+		if err != nil {
+			return err
+		}
+		original, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal proxy host: %w", err)
+		}
+		original = append(original, '\n')
+
+		f, err := os.CreateTemp("", fmt.Sprintf("npm-proxy-host-%d-*.json", host.ID))
+		if err != nil {
+			return fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		path := f.Name()
+		defer os.Remove(path)
+
+		_, err = f.Write(original)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write temporary file: %w", err)
+		}
+
+		var edited ProxyHost
+		for {
+			if err := runEditor(path); err != nil {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read edited file: %w", err)
+			}
+			if bytes.Equal(bytes.TrimSpace(content), bytes.TrimSpace(original)) {
+				fmt.Fprintln(out, "Edit cancelled, no changes made")
+				return nil
+			}
+
+			if edited, err = parseEditedHost(*host, content); err == nil {
+				break
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if !confirm("Edit again?") {
+				return fmt.Errorf("aborted, proxy host %d was not changed", host.ID)
+			}
+		}
+
+		updatedHost, err := client.UpdateProxyHost(host.ID, edited)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully updated proxy host with ID: %d\n", updatedHost.ID)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// SetProxyHostEnabled enables or disables a proxy host without changing its configuration
+func (c *APIClient) SetProxyHostEnabled(id int, enabled bool) error {
+	return c.setEnabled("proxy-hosts", "proxy host", id, enabled)
+}
+
+// setEnabled enables or disables a host of any type, given by the endpoint
+// and label of its type, without changing its configuration
+func (c *APIClient) setEnabled(endpoint, label string, id int, enabled bool) error {
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", fmt.Sprintf("/nginx/%s/%d/%s", endpoint, id, action), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %d not found", label, id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to %s %s, status: %d, body: %s", action, label, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// toggleProxyHosts returns the RunE of the enable and disable commands
+func toggleProxyHosts(enabled bool) func(cmd *cobra.Command, args []string) error {
+	return toggleHosts("proxy host", (*APIClient).SetProxyHostEnabled, enabled)
+}
+
+// toggleHosts returns the RunE of the enable and disable commands of a host
+// type, given by its label and the method enabling or disabling one host
+func toggleHosts(label string, setEnabled func(c *APIClient, id int, enabled bool) error, enabled bool) func(cmd *cobra.Command, args []string) error {
+	action, done := "disable", "Disabled"
+	if enabled {
+		action, done = "enable", "Enabled"
+	}
+
+	return func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		var result BatchResult
+		for _, id := range ids {
+			err := setEnabled(client, id, enabled)
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to %s %s %d: %v\n", action, label, id, err)
+				continue
+			}
+			fmt.Fprintf(out, "%s %s %d\n", done, label, id)
+		}
+
+		return result.Err()
+	}
+}
+
+var enableCmd = &cobra.Command{
+	Use:          "enable ID...",
+	Short:        "Enable proxy hosts",
+	Long:         `Enable proxy hosts by ID that were disabled with the disable command.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleProxyHosts(true),
+}
+
+var disableCmd = &cobra.Command{
+	Use:   "disable ID...",
+	Short: "Disable proxy hosts",
+	Long: `Disable proxy hosts by ID. Disabled hosts keep their configuration but serve
+no traffic until they are enabled again.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleProxyHosts(false),
+}
+
+func init() {
+	rootCmd.AddCommand(enableCmd)
+	rootCmd.AddCommand(disableCmd)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// ensureResult is the JSON output of the ensure command
+type ensureResult struct {
+	Action string `json:"action"`
+	ID     int    `json:"id"`
+}
+
+// proxyHostsEqual reports whether two hosts would be sent to the API identically
+func proxyHostsEqual(a, b ProxyHost) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+var ensureCmd = &cobra.Command{
+	Use:   "ensure",
+	Short: "Create or update a proxy host to match the given settings",
+	Long: `Make sure a proxy host for the domain exists with the given settings.
+
+A missing host is created and a host whose settings differ is updated in place;
+otherwise nothing is changed. Settings not given as flags are left as they are
+on existing hosts, so running the same command again is a no-op. The result is
+printed as Created, Updated or Unchanged.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		domainName, _ := cmd.Flags().GetString("domain")
+		forwardHost, _ := cmd.Flags().GetString("forward-host")
+		forwardPort, _ := cmd.Flags().GetInt("forward-port")
+		forwardScheme, _ := cmd.Flags().GetString("forward-scheme")
+		if domainName == "" || forwardHost == "" || forwardPort == 0 {
+			return fmt.Errorf("domain, forward-host, and forward-port are required")
+		}
+		if err := validateDomainName(domainName); err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+
+		matches := findProxyHostsByDomain(hosts, domainName)
+		if len(matches) > 1 {
+			return fmt.Errorf("domain %s is served by %d proxy hosts, refusing to guess which one to ensure", domainName, len(matches))
+		}
+
+		var existing *ProxyHost
+		desired := ProxyHost{
+			DomainNames:   []string{domainName},
+			ForwardScheme: forwardScheme,
+			ForwardHost:   forwardHost,
+			ForwardPort:   forwardPort,
+			Enabled:       true,
+			BlockExploits: true,
+		}
+		if len(matches) == 1 {
+			existing = &matches[0]
+			desired = *existing
+		}
+
+		if _, err := applyProxyHostFlags(cmd, &desired); err != nil {
+			return err
+		}
+		if err := resolveReferenceFlags(cmd, client, &desired); err != nil {
+			return err
+		}
+		if existing == nil || desired.ForwardHost != existing.ForwardHost || desired.ForwardPort != existing.ForwardPort {
+			if err := applyForwardLoopCheck(cmd, desired); err != nil {
+				return err
+			}
+		}
+
+		var result ensureResult
+		switch {
+		case existing == nil:
+			createdHost, err := client.CreateProxyHost(desired)
+			if err != nil {
+				return fmt.Errorf("failed to create proxy host: %w", err)
+			}
+			result = ensureResult{Action: "created", ID: createdHost.ID}
+		case proxyHostsEqual(*existing, desired):
+			result = ensureResult{Action: "unchanged", ID: existing.ID}
+		default:
+			if _, err := client.UpdateProxyHost(existing.ID, desired); err != nil {
+				return fmt.Errorf("failed to update proxy host: %w", err)
+			}
+			result = ensureResult{Action: "updated", ID: existing.ID}
+		}
+
+		if output == "json" {
+			return writeJSON(result)
+		}
+		switch result.Action {
+		case "created":
+			fmt.Fprintf(out, "Created proxy host %d (%s)\n", result.ID, domainName)
+		case "updated":
+			fmt.Fprintf(out, "Updated proxy host %d (%s)\n", result.ID, domainName)
+		default:
+			fmt.Fprintf(out, "Unchanged proxy host %d (%s)\n", result.ID, domainName)
+		}
+		return nil
+	},
+}
+
+func init() {
+	ensureCmd.Flags().String("domain", "", "Domain name that identifies the proxy host")
+	ensureCmd.Flags().String("forward-host", "", "Forward host")
+	ensureCmd.Flags().Int("forward-port", 0, "Forward port")
+	ensureCmd.Flags().String("forward-scheme", "http", "Forward scheme (http or https)")
+	addSSLFlags(ensureCmd)
+	addHostOptionFlags(ensureCmd)
+	addAccessListFlags(ensureCmd)
+	addLocationFlags(ensureCmd)
+	addAdvancedConfigFlag(ensureCmd)
+	addHostHeaderFlags(ensureCmd)
+	ensureCmd.Flags().Bool("force", false, "Apply the settings even if safety checks fail")
+
+	rootCmd.AddCommand(ensureCmd)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ExportFile is the document written by the export command
+type ExportFile struct {
+	ProxyHosts []ProxyHost `json:"proxy_hosts"`
+}
+
+// primaryDomain returns the first domain name of a proxy host
+func primaryDomain(host ProxyHost) string {
+	return firstDomain(host.DomainNames)
+}
+
+// firstDomain returns the first of a list of domain names, or "" when it is empty
+func firstDomain(domains []string) string {
+	if len(domains) == 0 {
+		return ""
+	}
+	return domains[0]
+}
+
+// stabilizeProxyHosts sorts the domain names of every host and then the hosts
+// themselves by primary domain and ID, so exports of the same configuration
+// are byte-for-byte identical. Map fields need no handling here because
+// encoding/json always marshals map keys in sorted order.
+func stabilizeProxyHosts(hosts []ProxyHost) []ProxyHost {
+	stable := make([]ProxyHost, len(hosts))
+	for i, host := range hosts {
+		domains := append([]string(nil), host.DomainNames...)
+		sort.Strings(domains)
+		host.DomainNames = domains
+		stable[i] = host
+	}
+
+	sort.SliceStable(stable, func(i, j int) bool {
+		if a, b := primaryDomain(stable[i]), primaryDomain(stable[j]); a != b {
+			return a < b
+		}
+		return stable[i].ID < stable[j].ID
+	})
+
+	return stable
+}
+
+// writeExport serializes proxy hosts as an indented export document
+func writeExport(w io.Writer, hosts []ProxyHost, stable bool) error {
+	if stable {
+		hosts = stabilizeProxyHosts(hosts)
+	}
+
+	jsonData, err := json.MarshalIndent(ExportFile{ProxyHosts: hosts}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export: %w", err)
+	}
+
+	if _, err := w.Write(append(jsonData, '\n')); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return nil
+}
+
+// defaultBackupDir returns the directory for backups that are made even when
+// no --backup-before directory is given
+func defaultBackupDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory for the backup: %w", err)
+	}
+	return filepath.Join(dir, "nginxproxymanager-cli", "backups"), nil
+}
+
+// backupProxyHosts writes the hosts to a new timestamped export file in dir
+// and returns its path. The backup can be restored with the import command.
+func backupProxyHosts(dir string, hosts []ProxyHost) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("npm-backup-%s.json", time.Now().Format("20060102-150405.000")))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create backup file: %w", err)
+	}
+
+	// A backup that isn't safely on disk must not let the destructive
+	// command go ahead, so a failing sync or close is an error too
+	err = writeExport(f, hosts, true)
+	if err == nil {
+		if err = f.Sync(); err != nil {
+			err = fmt.Errorf("failed to write backup file: %w", err)
+		}
+	}
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write backup file: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	fmt.Fprintf(os.Stderr, "Backed up %d proxy hosts to %s\n", len(hosts), path)
+	return path, nil
+}
+
+// proxyHostFields returns the JSON field names of a proxy host
+func proxyHostFields() map[string]bool {
+	jsonData, _ := json.Marshal(ProxyHost{})
+
+	var fields map[string]json.RawMessage
+	json.Unmarshal(jsonData, &fields)
+
+	names := make(map[string]bool, len(fields))
+	for name := range fields {
+		names[name] = true
+	}
+	return names
+}
+
+// parseFieldList splits a comma separated list of proxy host JSON fields and
+// makes sure every field exists
+func parseFieldList(value string) ([]string, error) {
+	known := proxyHostFields()
+
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("unknown proxy host field %q", field)
+		}
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields given")
+	}
+	return fields, nil
+}
+
+// selectProxyHostFields returns only the given JSON fields of a proxy host
+func selectProxyHostFields(host ProxyHost, fields []string) (map[string]json.RawMessage, error) {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal proxy host: %w", err)
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(jsonData, &all); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy host: %w", err)
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		selected[field] = all[field]
+	}
+	return selected, nil
+}
+
+// writeFieldExport serializes only the given fields of every proxy host,
+// keyed by primary domain
+func writeFieldExport(w io.Writer, hosts []ProxyHost, fields []string, stable bool) error {
+	if stable {
+		hosts = stabilizeProxyHosts(hosts)
+	}
+
+	export := make(map[string]map[string]json.RawMessage, len(hosts))
+	for _, host := range hosts {
+		domain := primaryDomain(host)
+		if _, exists := export[domain]; exists {
+			return fmt.Errorf("primary domain %q is used by more than one proxy host", domain)
+		}
+
+		selected, err := selectProxyHostFields(host, fields)
+		if err != nil {
+			return err
+		}
+		export[domain] = selected
+	}
+
+	jsonData, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export: %w", err)
+	}
+
+	if _, err := w.Write(append(jsonData, '\n')); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return nil
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all proxy hosts as JSON",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		stable, _ := cmd.Flags().GetBool("stable")
+		fieldList, _ := cmd.Flags().GetString("fields")
+
+		var fields []string
+		if fieldList != "" {
+			var err error
+			if fields, err = parseFieldList(fieldList); err != nil {
+				return err
+			}
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+
+		w := out
+		var f *os.File
+		if file != "" {
+			if f, err = os.Create(file); err != nil {
+				return fmt.Errorf("failed to create export file: %w", err)
+			}
+			w = f
+		}
+
+		if fields != nil {
+			err = writeFieldExport(w, hosts, fields, stable)
+		} else {
+			err = writeExport(w, hosts, stable)
+		}
+		if f != nil {
+			// A truncated export must not be reported as written
+			if closeErr := f.Close(); err == nil && closeErr != nil {
+				err = fmt.Errorf("failed to write export file: %w", closeErr)
+			}
+		}
+		if err != nil || file == "" {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Exported %d proxy hosts to %s\n", len(hosts), file)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringP("file", "f", "", "Write the export to a file instead of stdout")
+	exportCmd.Flags().Bool("stable", true, "Sort hosts and domain names for reproducible output")
+	exportCmd.Flags().String("fields", "", "Only export these comma separated fields, keyed by primary domain")
+
+	rootCmd.AddCommand(exportCmd)
+}
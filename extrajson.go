@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// flagFields maps the create and update flags to the proxy host fields they set
+var flagFields = map[string]string{
+	"domain":               "domain_names",
+	"forward-host":         "forward_host",
+	"forward-port":         "forward_port",
+	"forward-scheme":       "forward_scheme",
+	"certificate-id":       "certificate_id",
+	"certificate":          "certificate_id",
+	"access-list-id":       "access_list_id",
+	"access-list":          "access_list_id",
+	"ssl-forced":           "ssl_forced",
+	"no-ssl-redirect":      "ssl_forced",
+	"disabled":             "enabled",
+	"websockets":           "allow_websocket_upgrade",
+	"http2":                "http2_support",
+	"hsts":                 "hsts_enabled",
+	"hsts-subdomains":      "hsts_subdomains",
+	"location":             "locations",
+	"locations-file":       "locations",
+	"advanced-config-file": "advanced_config",
+	"preserve-host":        "advanced_config",
+	"no-preserve-host":     "advanced_config",
+}
+
+// addExtraJSONFlag registers the --extra-json flag
+func addExtraJSONFlag(cmd *cobra.Command) {
+	cmd.Flags().String("extra-json", "", "JSON object of additional proxy host fields, merged over the other flags")
+}
+
+// applyExtraJSON parses --extra-json and stores its fields on the host, where
+// they override everything else when the host is sent. Fields that replace a
+// value given by another flag are reported on stderr.
+func applyExtraJSON(cmd *cobra.Command, host *ProxyHost) error {
+	value, _ := cmd.Flags().GetString("extra-json")
+	if value == "" {
+		return nil
+	}
+
+	var extra map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &extra); err != nil {
+		return fmt.Errorf("invalid --extra-json, expected a JSON object: %w", err)
+	}
+
+	overridden := make(map[string]bool)
+	for name, field := range flagFields {
+		if _, set := extra[field]; set && cmd.Flags().Changed(name) {
+			overridden[field] = true
+		}
+	}
+
+	fields := make([]string, 0, len(overridden))
+	for field := range overridden {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Fprintf(os.Stderr, "Warning: --extra-json field %s overrides the value set by flags\n", field)
+	}
+
+	host.Extra = extra
+	return nil
+}
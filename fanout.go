@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// profileResult is the outcome of fetching items from one profile
+type profileResult[T any] struct {
+	Profile string
+	Items   []T
+	Err     error
+}
+
+// profileOutput is the JSON output for one profile with --all-profiles
+type profileOutput struct {
+	Profile string `json:"profile"`
+	Items   any    `json:"items,omitempty"`
+	Summary any    `json:"summary,omitempty"`
+	Count   *int   `json:"count,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// checkAllProfiles reads the --all-profiles flag of a command and rejects
+// combining it with --profile
+func checkAllProfiles(cmd *cobra.Command) (bool, error) {
+	allProfiles, _ := cmd.Flags().GetBool("all-profiles")
+	if allProfiles && profileName != "" {
+		return false, fmt.Errorf("--all-profiles and --profile cannot be used together")
+	}
+	return allProfiles, nil
+}
+
+// fanOutProfiles runs fetch against every configured profile concurrently.
+// Results are in profile name order and a failing profile doesn't stop the others.
+func fanOutProfiles[T any](fetch func(client *APIClient) ([]T, error)) ([]profileResult[T], error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	names := config.profileNames()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no profiles configured")
+	}
+
+	results := make([]profileResult[T], len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].Profile = name
+
+			client, err := newProfileClient(config, name)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].Items, results[i].Err = fetch(client)
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// reportProfileErrors prints the error of every failed profile to stderr. The
+// command only fails when no profile succeeded.
+func reportProfileErrors[T any](results []profileResult[T]) error {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Error: profile %s: %v\n", result.Profile, result.Err)
+		}
+	}
+
+	if failed == len(results) {
+		return fmt.Errorf("all %d profiles failed", failed)
+	}
+	return nil
+}
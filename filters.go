@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// proxyHostFilter decides whether a proxy host is shown
+type proxyHostFilter func(ProxyHost) bool
+
+// filterProxyHosts returns the hosts accepted by all filters
+func filterProxyHosts(hosts []ProxyHost, filters []proxyHostFilter) []ProxyHost {
+	var result []ProxyHost
+hosts:
+	for _, host := range hosts {
+		for _, keep := range filters {
+			if !keep(host) {
+				continue hosts
+			}
+		}
+		result = append(result, host)
+	}
+	return result
+}
+
+// globMatch reports whether a value matches a shell-style glob pattern like
+// *.example.com, ignoring case
+func globMatch(pattern, value string) bool {
+	matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return matched
+}
+
+// listFilters builds the proxy host filters from the flags of the list command
+func listFilters(cmd *cobra.Command) ([]proxyHostFilter, error) {
+	flags := cmd.Flags()
+	includeDisabled, _ := flags.GetBool("include-disabled")
+	disabledOnly, _ := flags.GetBool("disabled-only")
+	enabledOnly, _ := flags.GetBool("enabled")
+	domainPattern, _ := flags.GetString("domain")
+	forwardHostPattern, _ := flags.GetString("forward-host")
+	certID, _ := flags.GetInt("cert-id")
+	noCert, _ := flags.GetBool("no-cert")
+
+	// --enabled is a short form of --include-disabled=false
+	if enabledOnly {
+		includeDisabled = false
+	}
+
+	if disabledOnly && !includeDisabled {
+		return nil, fmt.Errorf("--disabled-only cannot be combined with --include-disabled=false")
+	}
+	if noCert && flags.Changed("cert-id") {
+		return nil, fmt.Errorf("--no-cert cannot be combined with --cert-id")
+	}
+	for _, pattern := range []string{domainPattern, forwardHostPattern} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q", pattern)
+		}
+	}
+
+	var filters []proxyHostFilter
+	if !includeDisabled {
+		filters = append(filters, func(host ProxyHost) bool { return host.Enabled })
+	}
+	if disabledOnly {
+		filters = append(filters, func(host ProxyHost) bool { return !host.Enabled })
+	}
+	if domainPattern != "" {
+		filters = append(filters, func(host ProxyHost) bool {
+			for _, domain := range host.DomainNames {
+				if globMatch(domainPattern, domain) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if forwardHostPattern != "" {
+		filters = append(filters, func(host ProxyHost) bool { return globMatch(forwardHostPattern, host.ForwardHost) })
+	}
+	if flags.Changed("cert-id") {
+		filters = append(filters, func(host ProxyHost) bool { return host.CertificateID == certID })
+	}
+	if noCert {
+		filters = append(filters, func(host ProxyHost) bool { return host.CertificateID == 0 })
+	}
+
+	return filters, nil
+}
+
+// parseFieldFilter parses a filter expression like enabled=false or
+// forward_host!=10.0.0.5 on a proxy host JSON field. Strings are compared
+// case-insensitively, and a list field like domain_names matches when any of
+// its elements does.
+func parseFieldFilter(expr string) (proxyHostFilter, error) {
+	field, value, ok := strings.Cut(expr, "!=")
+	negate := ok
+	if !ok {
+		field, value, ok = strings.Cut(expr, "=")
+	}
+	field = strings.TrimSpace(field)
+	if !ok || field == "" {
+		return nil, fmt.Errorf("invalid filter %q, expected field=value or field!=value", expr)
+	}
+	if !proxyHostFields()[field] {
+		return nil, fmt.Errorf("invalid filter %q, unknown proxy host field %q", expr, field)
+	}
+	value = strings.TrimSpace(value)
+
+	return func(host ProxyHost) bool {
+		return fieldMatches(host, field, value) != negate
+	}, nil
+}
+
+// fieldMatches reports whether a JSON field of the host has the given value
+func fieldMatches(host ProxyHost, field, value string) bool {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return false
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(jsonData, &fields); err != nil {
+		return false
+	}
+	raw := fields[field]
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for _, element := range list {
+			if jsonValueEquals(element, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return jsonValueEquals(raw, value)
+}
+
+// jsonValueEquals compares a JSON value with a value given on the command line
+func jsonValueEquals(raw json.RawMessage, value string) bool {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.EqualFold(s, value)
+	}
+	return strings.TrimSpace(string(raw)) == value
+}
+
+// parseFieldFilters parses all --filter expressions
+func parseFieldFilters(exprs []string) ([]proxyHostFilter, error) {
+	filters := make([]proxyHostFilter, 0, len(exprs))
+	for _, expr := range exprs {
+		filter, err := parseFieldFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ProxyHostDetails is a proxy host with its certificate, owner and access list
+// expanded. Raw keeps the complete record as sent by the API.
+type ProxyHostDetails struct {
+	Host ProxyHost
+	Raw  json.RawMessage
+}
+
+// GetProxyHostDetails fetches a single proxy host with its certificate, owner and access list expanded
+func (c *APIClient) GetProxyHostDetails(id int) (*ProxyHostDetails, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/proxy-hosts/%d?expand=certificate,owner,access_list", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("proxy host %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get proxy host, status: %d", resp.StatusCode)
+	}
+
+	details := ProxyHostDetails{}
+	if err := decodeJSON(resp.Body, &details.Raw); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy host: %w", err)
+	}
+	if err := json.Unmarshal(details.Raw, &details.Host); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy host: %w", err)
+	}
+
+	return &details, nil
+}
+
+// parseIDs parses proxy host IDs given as arguments
+func parseIDs(args []string) ([]int, error) {
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// printProxyHostDetails prints the full configuration of a proxy host
+func printProxyHostDetails(details *ProxyHostDetails) {
+	host := details.Host
+
+	fmt.Fprintf(out, "ID: %d\n", host.ID)
+	fmt.Fprintf(out, "Domain Names: %v\n", host.DomainNames)
+	fmt.Fprintf(out, "Forward: %s://%s:%d\n", host.ForwardScheme, host.ForwardHost, host.ForwardPort)
+	fmt.Fprintf(out, "Enabled: %t\n", host.Enabled)
+	fmt.Fprintf(out, "Online: %t\n", host.Meta.NginxOnline)
+	if host.Meta.NginxErr != "" {
+		fmt.Fprintf(out, "Nginx Error: %s\n", host.Meta.NginxErr)
+	}
+	fmt.Fprintf(out, "SSL: %s\n", sslStatus(host))
+
+	switch {
+	case host.Certificate != nil:
+		cert := host.Certificate
+		fmt.Fprintf(out, "Certificate: %d %s (%s, %s, expires %s)\n",
+			cert.ID, cert.NiceName, cert.Provider, strings.Join(cert.DomainNames, ", "), cert.ExpiresOn)
+	case host.CertificateID != 0:
+		fmt.Fprintf(out, "Certificate: %d\n", host.CertificateID)
+	}
+
+	switch {
+	case host.AccessList != nil:
+		fmt.Fprintf(out, "Access List: %d %s\n", host.AccessList.ID, host.AccessList.Name)
+	case host.AccessListID != 0:
+		fmt.Fprintf(out, "Access List: %d\n", host.AccessListID)
+	default:
+		fmt.Fprintln(out, "Access List: none (publicly accessible)")
+	}
+
+	if host.Owner != nil {
+		fmt.Fprintf(out, "Owner: %s <%s>\n", host.Owner.Name, host.Owner.Email)
+	}
+
+	fmt.Fprintf(out, "Websockets: %t\n", host.AllowWebsocketUpgrade)
+	fmt.Fprintf(out, "HTTP/2: %t\n", host.HTTP2Support)
+	fmt.Fprintf(out, "HSTS: %t (subdomains: %t)\n", host.HSTSEnabled, host.HSTSSubdomains)
+	fmt.Fprintf(out, "Host Header: %s\n", hostHeaderSetting(host.AdvancedConfig))
+	fmt.Fprintf(out, "Caching: %t\n", host.CachingEnabled)
+	fmt.Fprintf(out, "Block Exploits: %t\n", host.BlockExploits)
+	fmt.Fprintf(out, "Created: %s\n", host.CreatedOn)
+	fmt.Fprintf(out, "Modified: %s\n", host.ModifiedOn)
+
+	for _, location := range host.Locations {
+		fmt.Fprintf(out, "Location: %s -> %s://%s:%d\n", location.Path, location.ForwardScheme, location.ForwardHost, location.ForwardPort)
+	}
+
+	if host.AdvancedConfig != "" {
+		fmt.Fprintln(out, "Advanced Config:")
+		for _, line := range strings.Split(strings.TrimRight(host.AdvancedConfig, "\n"), "\n") {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+}
+
+var getCmd = &cobra.Command{
+	Use:     "get [ID]",
+	Aliases: []string{"show"},
+	Short:   "Show the full configuration of a proxy host",
+	Long: `Show the full configuration of a single proxy host, including its advanced
+config, certificate, access list and owner.
+
+The host is selected by ID or with --domain. With --output json the complete
+record is printed as returned by the API.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		domain, _ := cmd.Flags().GetString("domain")
+		matchWildcards, _ := cmd.Flags().GetBool("match-wildcards")
+
+		var id int
+		if len(args) == 1 {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			id = ids[0]
+		}
+		if id == 0 && domain == "" {
+			return fmt.Errorf("an ID or --domain is required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if domain != "" {
+			hosts, err := client.ListProxyHosts()
+			if err != nil {
+				return fmt.Errorf("failed to list proxy hosts: %w", err)
+			}
+
+			host, err := selectProxyHostByDomain(hosts, domain, matchWildcards, id)
+			if err != nil {
+				return err
+			}
+			id = host.ID
+		}
+
+		details, err := client.GetProxyHostDetails(id)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(details.Raw)
+		}
+		printProxyHostDetails(details)
+		return nil
+	},
+}
+
+func init() {
+	getCmd.Flags().String("domain", "", "Domain of the proxy host to show")
+	getCmd.Flags().Bool("match-wildcards", false, "Also match hosts with a wildcard domain covering --domain")
+
+	rootCmd.AddCommand(getCmd)
+}
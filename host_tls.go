@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// hostTLS holds the SSL settings that redirection and 404 hosts share with
+// proxy hosts
+type hostTLS struct {
+	CertificateID  int
+	SslForced      bool
+	HTTP2Support   bool
+	HSTSEnabled    bool
+	HSTSSubdomains bool
+}
+
+// status describes how the host serves HTTPS, like sslStatus of proxy hosts
+func (t hostTLS) status() string {
+	return sslStatus(ProxyHost{CertificateID: t.CertificateID, SslForced: t.SslForced})
+}
+
+// addTLSFlags registers the certificate, SSL redirect, HTTP/2 and HSTS flags
+// of host types other than proxy hosts
+func addTLSFlags(cmd *cobra.Command) {
+	addSSLFlags(cmd)
+	cmd.Flags().Bool("http2", false, "Enable HTTP/2 for HTTPS connections")
+	cmd.Flags().Bool("hsts", false, "Send a Strict-Transport-Security header (requires --ssl-forced)")
+	cmd.Flags().Bool("hsts-subdomains", false, "Include subdomains in the Strict-Transport-Security header (requires --hsts)")
+}
+
+// tlsFlagsChanged reports whether any flag of addTLSFlags was given
+func tlsFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range []string{"certificate-id", "certificate", "ssl-forced", "no-ssl-redirect", "http2", "hsts", "hsts-subdomains"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTLSFlags applies the flags of addTLSFlags to the SSL settings of a
+// host serving domains, with the same checks and warnings as for proxy hosts,
+// and looks up a certificate given by name
+func applyTLSFlags(cmd *cobra.Command, client *APIClient, domains []string, tls *hostTLS) error {
+	host := ProxyHost{
+		DomainNames:    domains,
+		CertificateID:  tls.CertificateID,
+		SslForced:      tls.SslForced,
+		HTTP2Support:   tls.HTTP2Support,
+		HSTSEnabled:    tls.HSTSEnabled,
+		HSTSSubdomains: tls.HSTSSubdomains,
+	}
+	if err := applySSLFlags(cmd, &host); err != nil {
+		return err
+	}
+	if err := applyHostOptionFlags(cmd, &host); err != nil {
+		return err
+	}
+	if err := resolveReferenceFlags(cmd, client, &host); err != nil {
+		return err
+	}
+
+	*tls = hostTLS{
+		CertificateID:  host.CertificateID,
+		SslForced:      host.SslForced,
+		HTTP2Support:   host.HTTP2Support,
+		HSTSEnabled:    host.HSTSEnabled,
+		HSTSSubdomains: host.HSTSSubdomains,
+	}
+	// HSTS left over from a removed certificate has no effect, drop it
+	if tls.CertificateID == 0 && !tls.SslForced {
+		tls.HSTSEnabled, tls.HSTSSubdomains = false, false
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readProxyHostFile reads a proxy host definition in YAML or JSON from a file,
+// or from stdin when path is "-", and applies its fields over base. Fields use
+// the JSON names of the API, like domain_names and forward_port.
+func readProxyHostFile(path string, base ProxyHost) (ProxyHost, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return base, fmt.Errorf("failed to read proxy host file: %w", err)
+	}
+
+	// YAML is a superset of JSON, so one parser covers both formats
+	var definition map[string]any
+	if err := yaml.Unmarshal(content, &definition); err != nil {
+		return base, fmt.Errorf("failed to parse proxy host file: %w", err)
+	}
+
+	jsonData, err := json.Marshal(definition)
+	if err != nil {
+		return base, fmt.Errorf("failed to convert proxy host file: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(jsonData, &fields); err != nil {
+		return base, fmt.Errorf("failed to convert proxy host file: %w", err)
+	}
+	if err := checkEditableFields(fields); err != nil {
+		return base, fmt.Errorf("invalid proxy host file: %w", err)
+	}
+
+	host, err := mergeProxyHostFields(base, fields)
+	if err != nil {
+		return base, fmt.Errorf("invalid proxy host file: %w", err)
+	}
+	return host, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hostHeaderPattern matches a proxy_set_header Host directive on its own line
+var hostHeaderPattern = regexp.MustCompile(`(?mi)^[ \t]*proxy_set_header[ \t]+Host[ \t]+([^;\n]*);[ \t]*$\n?`)
+
+const (
+	preserveHostDirective   = "proxy_set_header Host $host;"
+	noPreserveHostDirective = "proxy_set_header Host $proxy_host;"
+)
+
+// setHostHeader makes the advanced config send the original Host header to
+// the backend, or the backend's own host name when preserve is false. An
+// existing Host directive is replaced in place and duplicates are dropped, so
+// applying the same setting again leaves the config unchanged.
+func setHostHeader(config string, preserve bool) string {
+	directive := preserveHostDirective
+	if !preserve {
+		directive = noPreserveHostDirective
+	}
+
+	replaced := false
+	config = hostHeaderPattern.ReplaceAllStringFunc(config, func(match string) string {
+		if replaced {
+			return ""
+		}
+		replaced = true
+
+		indent := match[:len(match)-len(strings.TrimLeft(match, " \t"))]
+		newline := ""
+		if strings.HasSuffix(match, "\n") {
+			newline = "\n"
+		}
+		return indent + directive + newline
+	})
+	if replaced {
+		return config
+	}
+
+	if config != "" && !strings.HasSuffix(config, "\n") {
+		config += "\n"
+	}
+	return config + directive + "\n"
+}
+
+// hostHeaderSetting describes which Host header the advanced config sends to the backend
+func hostHeaderSetting(config string) string {
+	match := hostHeaderPattern.FindStringSubmatch(config)
+	if match == nil {
+		return "original host (NPM default)"
+	}
+
+	switch value := strings.TrimSpace(match[1]); value {
+	case "$host", "$http_host":
+		return "original host"
+	case "$proxy_host":
+		return "backend host"
+	default:
+		return "custom: " + value
+	}
+}
+
+// addHostHeaderFlags registers the flags controlling the Host header sent to the backend
+func addHostHeaderFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("preserve-host", false, "Send the original Host header to the backend")
+	cmd.Flags().Bool("no-preserve-host", false, "Send the backend's host name as Host header")
+	cmd.MarkFlagsMutuallyExclusive("preserve-host", "no-preserve-host")
+}
+
+// applyHostHeaderFlags updates the advanced config of the host when one of
+// the Host header flags was given and reports whether it did
+func applyHostHeaderFlags(cmd *cobra.Command, host *ProxyHost) bool {
+	flags := cmd.Flags()
+
+	switch {
+	case flags.Changed("preserve-host"):
+		preserve, _ := flags.GetBool("preserve-host")
+		host.AdvancedConfig = setHostHeader(host.AdvancedConfig, preserve)
+	case flags.Changed("no-preserve-host"):
+		noPreserve, _ := flags.GetBool("no-preserve-host")
+		host.AdvancedConfig = setHostHeader(host.AdvancedConfig, !noPreserve)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// addHostOptionFlags registers the flags for websockets, HTTP/2 and HSTS
+func addHostOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("websockets", false, "Allow websocket upgrades")
+	cmd.Flags().Bool("http2", false, "Enable HTTP/2 for HTTPS connections")
+	cmd.Flags().Bool("hsts", false, "Send a Strict-Transport-Security header (requires --ssl-forced)")
+	cmd.Flags().Bool("hsts-subdomains", false, "Include subdomains in the Strict-Transport-Security header (requires --hsts)")
+}
+
+// applyHostOptionFlags applies the websocket, HTTP/2 and HSTS flags that were
+// given on the command line. HSTS is only checked against the SSL settings
+// when one of its flags was given, so hosts that already have inconsistent
+// settings can still be updated otherwise.
+func applyHostOptionFlags(cmd *cobra.Command, host *ProxyHost) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("websockets") {
+		host.AllowWebsocketUpgrade, _ = flags.GetBool("websockets")
+	}
+	if flags.Changed("http2") {
+		host.HTTP2Support, _ = flags.GetBool("http2")
+	}
+	if flags.Changed("hsts") {
+		host.HSTSEnabled, _ = flags.GetBool("hsts")
+	}
+	if flags.Changed("hsts-subdomains") {
+		host.HSTSSubdomains, _ = flags.GetBool("hsts-subdomains")
+	}
+
+	if !flags.Changed("hsts") && !flags.Changed("hsts-subdomains") {
+		return nil
+	}
+	if host.HSTSEnabled && !host.SslForced {
+		return fmt.Errorf("--hsts requires SSL to be forced, browsers ignore HSTS on plain HTTP")
+	}
+	if host.HSTSSubdomains && !host.HSTSEnabled {
+		return fmt.Errorf("--hsts-subdomains requires --hsts")
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// hostTypes maps the names accepted by hosts list --type to the host types
+var hostTypes = map[string]string{
+	"proxy":       "proxy host",
+	"redirection": "redirection host",
+	"dead":        "404 host",
+	"stream":      "stream",
+}
+
+// hostEntry is a host of any type as shown by hosts list
+type hostEntry struct {
+	Type          string   `json:"type"`
+	ID            int      `json:"id"`
+	DomainNames   []string `json:"domain_names,omitempty"`
+	IncomingPort  int      `json:"incoming_port,omitempty"`
+	Target        string   `json:"target"`
+	CertificateID int      `json:"certificate_id"`
+	SSL           string   `json:"ssl"`
+	Enabled       bool     `json:"enabled"`
+}
+
+// name returns the domains of a host, or the incoming port of a stream
+func (e hostEntry) name() string {
+	if e.Type == "stream" {
+		return fmt.Sprintf("port %d", e.IncomingPort)
+	}
+	return strings.Join(e.DomainNames, ",")
+}
+
+// listHostEntries fetches the hosts of all four types
+func listHostEntries(client *APIClient) ([]hostEntry, error) {
+	hosts, err := listAllHosts(client)
+	if err != nil {
+		return nil, err
+	}
+	streams, err := client.ListStreams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list streams: %w", err)
+	}
+
+	var entries []hostEntry
+	for _, host := range hosts.ProxyHosts {
+		entries = append(entries, hostEntry{"proxy host", host.ID, host.DomainNames, 0,
+			hostTarget(host).String(), host.CertificateID, sslStatus(host), host.Enabled})
+	}
+	for _, host := range hosts.RedirectionHosts {
+		entries = append(entries, hostEntry{"redirection host", host.ID, host.DomainNames, 0,
+			fmt.Sprintf("%d %s", host.ForwardHTTPCode, redirectTarget(host)), host.CertificateID, host.tls().status(), host.Enabled})
+	}
+	for _, host := range hosts.DeadHosts {
+		entries = append(entries, hostEntry{"404 host", host.ID, host.DomainNames, 0,
+			"404", host.CertificateID, host.tls().status(), host.Enabled})
+	}
+	for _, stream := range streams {
+		entries = append(entries, hostEntry{"stream", stream.ID, nil, stream.IncomingPort,
+			fmt.Sprintf("%s:%d (%s)", stream.ForwardingHost, stream.ForwardingPort, streamProtocols(stream)), 0, "-", stream.Enabled})
+	}
+	return entries, nil
+}
+
+// servingEntries returns the hosts serving a domain name, those with the exact
+// name first as nginx prefers them over wildcards
+func servingEntries(entries []hostEntry, domain string) []hostEntry {
+	var exact, wildcard []hostEntry
+	for _, entry := range entries {
+		for _, name := range entry.DomainNames {
+			if strings.EqualFold(name, domain) {
+				exact = append(exact, entry)
+				break
+			}
+			if matchesDomain(name, domain) {
+				wildcard = append(wildcard, entry)
+				break
+			}
+		}
+	}
+	return append(exact, wildcard...)
+}
+
+var hostsCmd = &cobra.Command{
+	Use:   "hosts",
+	Short: "Show proxy, redirection and 404 hosts and streams together",
+}
+
+var hostsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List hosts of all types in one table",
+	Long: `List proxy hosts, redirection hosts, 404 hosts and streams in one table with
+their type.
+
+--domain looks up which hosts serve a domain name, including hosts with a
+matching wildcard domain like *.example.com. Hosts with the exact name come
+first, nginx prefers them over wildcards. The command fails when no host
+serves the name.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		typeNames, _ := cmd.Flags().GetStringSlice("type")
+		enabledOnly, _ := cmd.Flags().GetBool("enabled")
+		disabledOnly, _ := cmd.Flags().GetBool("disabled")
+		domain, _ := cmd.Flags().GetString("domain")
+		match, _ := cmd.Flags().GetString("match")
+		certID, _ := cmd.Flags().GetInt("cert-id")
+
+		types := make(map[string]bool)
+		for _, name := range typeNames {
+			hostType, ok := hostTypes[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return fmt.Errorf("invalid type %q, expected proxy, redirection, dead or stream", name)
+			}
+			types[hostType] = true
+		}
+		if enabledOnly && disabledOnly {
+			return fmt.Errorf("--enabled cannot be combined with --disabled")
+		}
+		if _, err := path.Match(match, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q", match)
+		}
+		domain = strings.TrimSpace(domain)
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		entries, err := listHostEntries(client)
+		if err != nil {
+			return err
+		}
+		if domain != "" {
+			entries = servingEntries(entries, domain)
+		}
+
+		var result []hostEntry
+		for _, entry := range entries {
+			switch {
+			case len(types) > 0 && !types[entry.Type]:
+			case enabledOnly && !entry.Enabled, disabledOnly && entry.Enabled:
+			case cmd.Flags().Changed("cert-id") && entry.CertificateID != certID:
+			case match != "" && !anyGlobMatch(match, entry.DomainNames):
+			default:
+				result = append(result, entry)
+			}
+		}
+		if domain != "" && len(result) == 0 {
+			return fmt.Errorf("no host serves %s", domain)
+		}
+
+		if output == "json" {
+			return writeJSON(append([]hostEntry{}, result...))
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tID\tDOMAINS\tTARGET\tSSL\tENABLED")
+		for _, entry := range result {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%t\n", entry.Type, entry.ID, entry.name(), entry.Target, entry.SSL, entry.Enabled)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+// anyGlobMatch reports whether any of the values matches the glob pattern
+func anyGlobMatch(pattern string, values []string) bool {
+	for _, value := range values {
+		if globMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	hostsListCmd.Flags().StringSlice("type", nil, "Only show these host types: proxy, redirection, dead or stream (repeatable or comma separated)")
+	hostsListCmd.Flags().Bool("enabled", false, "Only show enabled hosts")
+	hostsListCmd.Flags().Bool("disabled", false, "Only show disabled hosts")
+	hostsListCmd.Flags().String("domain", "", "Only show the hosts serving this domain name, directly or by a wildcard")
+	hostsListCmd.Flags().String("match", "", "Only show hosts with a domain matching this glob, like *.example.com")
+	hostsListCmd.Flags().Int("cert-id", 0, "Only show hosts using this certificate")
+
+	hostsCmd.AddCommand(hostsListCmd)
+	rootCmd.AddCommand(hostsCmd)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// templateVarPattern matches ${VAR} references in import files. Plain $VAR is
+// left alone because nginx variables like $host appear in advanced configs.
+var templateVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// parseVars parses key=value pairs given with --var
+func parseVars(values []string) (map[string]string, error) {
+	vars := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value", value)
+		}
+		vars[key] = val
+	}
+	return vars, nil
+}
+
+// substituteVariables replaces ${VAR} references with values from vars or,
+// failing that, the environment. Values are JSON escaped so they can be used
+// inside JSON strings. Unresolved variables are an error unless allowUnset is
+// set, in which case they become empty.
+func substituteVariables(content []byte, vars map[string]string, allowUnset bool) ([]byte, error) {
+	var unresolved []string
+	seen := make(map[string]bool)
+
+	result := templateVarPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		name := string(templateVarPattern.FindSubmatch(match)[1])
+
+		value, ok := vars[name]
+		if !ok {
+			value, ok = os.LookupEnv(name)
+		}
+		if !ok && !seen[name] {
+			seen[name] = true
+			unresolved = append(unresolved, name)
+		}
+
+		escaped, _ := json.Marshal(value)
+		return escaped[1 : len(escaped)-1]
+	})
+
+	if len(unresolved) > 0 && !allowUnset {
+		return nil, fmt.Errorf("unresolved variables: %s (use --var or --allow-unset)", strings.Join(unresolved, ", "))
+	}
+
+	return result, nil
+}
+
+// mergeProxyHostFields overlays the given JSON fields onto a proxy host
+func mergeProxyHostFields(host ProxyHost, fields map[string]json.RawMessage) (ProxyHost, error) {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return host, fmt.Errorf("failed to marshal proxy host: %w", err)
+	}
+
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(jsonData, &merged); err != nil {
+		return host, fmt.Errorf("failed to decode proxy host: %w", err)
+	}
+	for field, value := range fields {
+		merged[field] = value
+	}
+
+	if jsonData, err = json.Marshal(merged); err != nil {
+		return host, fmt.Errorf("failed to marshal merged proxy host: %w", err)
+	}
+
+	var result ProxyHost
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		return host, fmt.Errorf("failed to decode merged proxy host: %w", err)
+	}
+	return result, nil
+}
+
+// importProxyHost creates the host, or updates the existing host serving its primary domain
+func importProxyHost(client *APIClient, existing []ProxyHost, host ProxyHost) (string, *ProxyHost, error) {
+	matches := findProxyHostsByDomain(existing, primaryDomain(host))
+	switch len(matches) {
+	case 0:
+		createdHost, err := client.CreateProxyHost(host)
+		return "Created", createdHost, err
+	case 1:
+		host.ID = matches[0].ID
+		updatedHost, err := client.UpdateProxyHost(matches[0].ID, host)
+		return "Updated", updatedHost, err
+	default:
+		return "", nil, fmt.Errorf("domain is served by %d proxy hosts", len(matches))
+	}
+}
+
+// importProxyHostFields applies only the given fields to the existing host
+// serving domain. Field-limited imports never create hosts.
+func importProxyHostFields(client *APIClient, existing []ProxyHost, domain string, fields []string, values map[string]json.RawMessage) (*ProxyHost, error) {
+	matches := findProxyHostsByDomain(existing, domain)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no proxy host found, refusing to create hosts in field-limited import mode")
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("domain is served by %d proxy hosts", len(matches))
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := values[field]; ok {
+			selected[field] = value
+		}
+	}
+
+	host, err := mergeProxyHostFields(matches[0], selected)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.UpdateProxyHost(host.ID, host)
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import proxy hosts from an export file",
+	Long: `Import proxy hosts from a file written by the export command.
+
+Hosts whose primary domain already exists are updated, all others are created.
+With --fields, the file must be a field-limited export and only the given fields
+are merged into existing hosts; no hosts are created in that mode.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		file, _ := cmd.Flags().GetString("file")
+		if file == "" {
+			return fmt.Errorf("file is required")
+		}
+
+		fieldList, _ := cmd.Flags().GetString("fields")
+		var fields []string
+		if fieldList != "" {
+			var err error
+			if fields, err = parseFieldList(fieldList); err != nil {
+				return err
+			}
+		}
+
+		varValues, _ := cmd.Flags().GetStringArray("var")
+		vars, err := parseVars(varValues)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read import file: %w", err)
+		}
+
+		allowUnset, _ := cmd.Flags().GetBool("allow-unset")
+		if content, err = substituteVariables(content, vars, allowUnset); err != nil {
+			return err
+		}
+
+		var export ExportFile
+		var fieldExport map[string]map[string]json.RawMessage
+		if fields != nil {
+			err = json.Unmarshal(content, &fieldExport)
+		} else {
+			err = json.Unmarshal(content, &export)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse import file: %w", err)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		existing, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+
+		timeoutPerItem, _ := cmd.Flags().GetDuration("timeout-per-item")
+
+		var result BatchResult
+		if fields != nil {
+			domains := make([]string, 0, len(fieldExport))
+			for domain := range fieldExport {
+				domains = append(domains, domain)
+			}
+			sort.Strings(domains)
+
+			for _, domain := range domains {
+				var host *ProxyHost
+				err := client.runWithTimeout(timeoutPerItem, func(client *APIClient) (err error) {
+					host, err = importProxyHostFields(client, existing, domain, fields, fieldExport[domain])
+					return err
+				})
+				result.Record(err)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to import %s: %v\n", domain, err)
+					continue
+				}
+				fmt.Fprintf(out, "Updated proxy host %d (%s)\n", host.ID, domain)
+			}
+		} else {
+			for _, host := range export.ProxyHosts {
+				var action string
+				var imported *ProxyHost
+				err := client.runWithTimeout(timeoutPerItem, func(client *APIClient) (err error) {
+					action, imported, err = importProxyHost(client, existing, host)
+					return err
+				})
+				result.Record(err)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to import %s: %v\n", primaryDomain(host), err)
+					continue
+				}
+				fmt.Fprintf(out, "%s proxy host %d (%s)\n", action, imported.ID, primaryDomain(host))
+			}
+		}
+
+		fmt.Fprintf(out, "Import finished: %s\n", result)
+		return result.Err()
+	},
+}
+
+func init() {
+	importCmd.Flags().StringP("file", "f", "", "Export file to import")
+	importCmd.Flags().String("fields", "", "Only apply these comma separated fields to existing hosts")
+	importCmd.Flags().StringArray("var", nil, "Value for a ${VAR} reference in the file as key=value (repeatable)")
+	importCmd.Flags().Duration("timeout-per-item", 0, "Maximum time for importing a single proxy host, 0 for no limit")
+	importCmd.Flags().Bool("allow-unset", false, "Replace unresolved ${VAR} references with empty values instead of failing")
+
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// traefikConfig is the part of a Traefik dynamic configuration describing HTTP routing
+type traefikConfig struct {
+	HTTP struct {
+		Routers  map[string]traefikRouter  `yaml:"routers" toml:"routers"`
+		Services map[string]traefikService `yaml:"services" toml:"services"`
+	} `yaml:"http" toml:"http"`
+}
+
+// traefikRouter routes requests matching a rule to a service
+type traefikRouter struct {
+	Rule        string         `yaml:"rule" toml:"rule"`
+	Service     string         `yaml:"service" toml:"service"`
+	Middlewares []string       `yaml:"middlewares" toml:"middlewares"`
+	TLS         map[string]any `yaml:"tls" toml:"tls"`
+}
+
+// traefikService forwards requests to a set of servers
+type traefikService struct {
+	LoadBalancer *struct {
+		Servers []struct {
+			URL string `yaml:"url" toml:"url"`
+		} `yaml:"servers" toml:"servers"`
+	} `yaml:"loadBalancer" toml:"loadBalancer"`
+}
+
+var (
+	// traefikHostPattern matches a Host matcher with one or more backquoted or quoted domains
+	traefikHostPattern = regexp.MustCompile("^Host\\(\\s*([^()]*)\\)$")
+	// traefikDomainPattern extracts the domains from the arguments of a Host matcher
+	traefikDomainPattern = regexp.MustCompile("[`\"]([^`\"]+)[`\"]")
+)
+
+// parseTraefikConfig reads a YAML or TOML dynamic configuration, chosen by file extension
+func parseTraefikConfig(path string) (*traefikConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Traefik config: %w", err)
+	}
+
+	var config traefikConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(content, &config)
+	case ".toml":
+		err = toml.Unmarshal(content, &config)
+	default:
+		return nil, fmt.Errorf("unknown Traefik config format %q, expected .yml, .yaml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Traefik config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// traefikRuleDomains returns the domains of a rule made only of Host
+// matchers combined with ||. Any other matcher can't be expressed as a proxy host.
+func traefikRuleDomains(rule string) ([]string, error) {
+	var domains []string
+	for _, part := range strings.Split(rule, "||") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "(") && strings.HasSuffix(part, ")") {
+			part = strings.TrimSpace(part[1 : len(part)-1])
+		}
+
+		match := traefikHostPattern.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("rule %q uses matchers other than Host, such as path prefixes", rule)
+		}
+		for _, domain := range traefikDomainPattern.FindAllStringSubmatch(match[1], -1) {
+			domains = append(domains, strings.ToLower(domain[1]))
+		}
+	}
+
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("rule %q has no domain", rule)
+	}
+	return domains, nil
+}
+
+// traefikForward returns the forward target of a service from its first server URL
+func traefikForward(service traefikService) (scheme, host string, port int, err error) {
+	if service.LoadBalancer == nil || len(service.LoadBalancer.Servers) == 0 {
+		return "", "", 0, fmt.Errorf("service has no load balancer servers, weighted and mirroring services are not supported")
+	}
+
+	u, err := url.Parse(service.LoadBalancer.Servers[0].URL)
+	if err != nil || u.Hostname() == "" {
+		return "", "", 0, fmt.Errorf("invalid server URL %q", service.LoadBalancer.Servers[0].URL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", 0, fmt.Errorf("unsupported server URL scheme %q", u.Scheme)
+	}
+
+	port = 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		if port, err = strconv.Atoi(u.Port()); err != nil {
+			return "", "", 0, fmt.Errorf("invalid port in server URL %q", u.String())
+		}
+	}
+
+	return u.Scheme, u.Hostname(), port, nil
+}
+
+// translateTraefikRouter builds a proxy host from a router and its service.
+// Notes describe settings that were dropped in the translation.
+func translateTraefikRouter(config *traefikConfig, router traefikRouter) (ProxyHost, []string, error) {
+	domains, err := traefikRuleDomains(router.Rule)
+	if err != nil {
+		return ProxyHost{}, nil, err
+	}
+
+	// Services of other providers are referenced as name@provider
+	serviceName, _, _ := strings.Cut(router.Service, "@")
+	service, ok := config.HTTP.Services[serviceName]
+	if !ok {
+		return ProxyHost{}, nil, fmt.Errorf("service %q not found", router.Service)
+	}
+
+	scheme, forwardHost, forwardPort, err := traefikForward(service)
+	if err != nil {
+		return ProxyHost{}, nil, fmt.Errorf("service %q: %w", router.Service, err)
+	}
+
+	var notes []string
+	if servers := len(service.LoadBalancer.Servers); servers > 1 {
+		notes = append(notes, fmt.Sprintf("only the first of %d servers is used", servers))
+	}
+	if len(router.Middlewares) > 0 {
+		notes = append(notes, fmt.Sprintf("middlewares %s are not translated", strings.Join(router.Middlewares, ", ")))
+	}
+	if router.TLS != nil {
+		notes = append(notes, "TLS is enabled in Traefik, assign a certificate in NPM")
+	}
+
+	host := ProxyHost{
+		DomainNames:   domains,
+		ForwardScheme: scheme,
+		ForwardHost:   forwardHost,
+		ForwardPort:   forwardPort,
+		Enabled:       true,
+		BlockExploits: true,
+	}
+	return host, notes, nil
+}
+
+var importTraefikCmd = &cobra.Command{
+	Use:   "import-traefik",
+	Short: "Create proxy hosts from the routers of a Traefik dynamic configuration",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		file, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if file == "" {
+			return fmt.Errorf("file is required")
+		}
+
+		config, err := parseTraefikConfig(file)
+		if err != nil {
+			return err
+		}
+		if len(config.HTTP.Routers) == 0 {
+			return fmt.Errorf("no HTTP routers found in %s", file)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		existing, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+
+		names := make([]string, 0, len(config.HTTP.Routers))
+		for name := range config.HTTP.Routers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var result BatchResult
+		skipped := 0
+		for _, name := range names {
+			host, notes, err := translateTraefikRouter(config, config.HTTP.Routers[name])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipped router %s: %v\n", name, err)
+				skipped++
+				continue
+			}
+
+			if matches := findProxyHostsByDomain(existing, host.DomainNames[0]); len(matches) > 0 {
+				fmt.Fprintf(os.Stderr, "Skipped router %s: %s is already served by proxy host %d\n", name, host.DomainNames[0], matches[0].ID)
+				skipped++
+				continue
+			}
+
+			for _, note := range notes {
+				fmt.Fprintf(os.Stderr, "Warning: router %s: %s\n", name, note)
+			}
+
+			forward := fmt.Sprintf("%s://%s:%d", host.ForwardScheme, host.ForwardHost, host.ForwardPort)
+			if dryRun {
+				fmt.Fprintf(out, "Would create proxy host %v -> %s from router %s\n", host.DomainNames, forward, name)
+				continue
+			}
+
+			createdHost, err := client.CreateProxyHost(host)
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to create proxy host for router %s: %v\n", name, err)
+				continue
+			}
+			fmt.Fprintf(out, "Created proxy host %d %v -> %s from router %s\n", createdHost.ID, createdHost.DomainNames, forward, name)
+		}
+
+		if dryRun {
+			fmt.Fprintf(out, "Dry run: %d routers can be imported, %d skipped\n", len(names)-skipped, skipped)
+			return nil
+		}
+
+		fmt.Fprintf(out, "Import finished: %s, %d routers skipped\n", result, skipped)
+		return result.Err()
+	},
+}
+
+func init() {
+	importTraefikCmd.Flags().StringP("file", "f", "", "Traefik dynamic configuration file (.yml, .yaml or .toml)")
+	importTraefikCmd.Flags().Bool("dry-run", false, "Only show the proxy hosts that would be created")
+
+	rootCmd.AddCommand(importTraefikCmd)
+}
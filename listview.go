@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// listColumn is a column of the list table selected with --columns
+type listColumn struct {
+	Header string
+	Value  func(ProxyHost) string
+}
+
+// listColumns are the columns available to --columns
+var listColumns = map[string]listColumn{
+	"id":           {"ID", func(h ProxyHost) string { return strconv.Itoa(h.ID) }},
+	"domains":      {"DOMAINS", func(h ProxyHost) string { return strings.Join(h.DomainNames, ",") }},
+	"forward":      {"FORWARD", func(h ProxyHost) string { return hostTarget(h).String() }},
+	"ssl":          {"SSL", sslStatus},
+	"enabled":      {"ENABLED", func(h ProxyHost) string { return strconv.FormatBool(h.Enabled) }},
+	"online":       {"ONLINE", func(h ProxyHost) string { return strconv.FormatBool(h.Meta.NginxOnline) }},
+	"certificate":  {"CERTIFICATE", certificateName},
+	"cert_expires": {"CERT EXPIRES", certificateExpiry},
+	"access_list":  {"ACCESS LIST", accessListName},
+	"owner":        {"OWNER", ownerName},
+	"created_on":   {"CREATED", func(h ProxyHost) string { return h.CreatedOn }},
+	"modified_on":  {"MODIFIED", func(h ProxyHost) string { return h.ModifiedOn }},
+}
+
+// certificateName returns the name of the expanded certificate of a host,
+// falling back to its ID, or "-" without a certificate
+func certificateName(h ProxyHost) string {
+	switch {
+	case h.CertificateID == 0:
+		return "-"
+	case h.Certificate != nil:
+		return h.Certificate.NiceName
+	default:
+		return strconv.Itoa(h.CertificateID)
+	}
+}
+
+// certificateExpiry returns the expiry date of the expanded certificate of a host, or "-"
+func certificateExpiry(h ProxyHost) string {
+	if h.CertificateID == 0 || h.Certificate == nil {
+		return "-"
+	}
+	expiry, err := h.Certificate.Expiry()
+	if err != nil {
+		return "-"
+	}
+	return expiry.Format(time.DateOnly)
+}
+
+// certificateLabel describes the certificate of a host for the list output, like "example.com (expires 2025-03-01)"
+func certificateLabel(h ProxyHost) string {
+	if h.Certificate == nil {
+		return strconv.Itoa(h.CertificateID)
+	}
+	if expiry := certificateExpiry(h); expiry != "-" {
+		return fmt.Sprintf("%s (expires %s)", certificateName(h), expiry)
+	}
+	return certificateName(h)
+}
+
+// accessListName returns the name of the expanded access list of a host,
+// falling back to its ID, or "-" without an access list
+func accessListName(h ProxyHost) string {
+	switch {
+	case h.AccessListID == 0:
+		return "-"
+	case h.AccessList != nil:
+		return h.AccessList.Name
+	default:
+		return strconv.Itoa(h.AccessListID)
+	}
+}
+
+// ownerName returns the name of the expanded owner of a host, or "-"
+func ownerName(h ProxyHost) string {
+	if h.Owner == nil {
+		return "-"
+	}
+	return h.Owner.Name
+}
+
+// listSortKeys are the orderings available to --sort-by
+var listSortKeys = map[string]func(a, b ProxyHost) bool{
+	"id": func(a, b ProxyHost) bool { return a.ID < b.ID },
+	"domain": func(a, b ProxyHost) bool {
+		return strings.ToLower(primaryDomain(a)) < strings.ToLower(primaryDomain(b))
+	},
+	"modified_on": func(a, b ProxyHost) bool { return a.ModifiedOn < b.ModifiedOn },
+}
+
+// sortedNames returns the keys of a column or sort key map for error messages
+func sortedNames[T any](m map[string]T) string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// listView holds how list orders and prints proxy hosts
+type listView struct {
+	SortBy  string
+	Columns []string
+}
+
+// parseListView validates --sort-by and --columns
+func parseListView(sortBy, columns string) (listView, error) {
+	if _, ok := listSortKeys[sortBy]; !ok {
+		return listView{}, fmt.Errorf("invalid --sort-by %q, expected one of %s", sortBy, sortedNames(listSortKeys))
+	}
+
+	view := listView{SortBy: sortBy}
+	if columns == "" {
+		return view, nil
+	}
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if _, ok := listColumns[column]; !ok {
+			return listView{}, fmt.Errorf("invalid column %q, expected one of %s", column, sortedNames(listColumns))
+		}
+		view.Columns = append(view.Columns, column)
+	}
+	return view, nil
+}
+
+// sort orders the hosts in place. Hosts that compare equal keep the order of their IDs.
+func (v listView) sort(hosts []ProxyHost) {
+	less := listSortKeys[v.SortBy]
+	sort.SliceStable(hosts, func(i, j int) bool {
+		if less(hosts[i], hosts[j]) {
+			return true
+		}
+		if less(hosts[j], hosts[i]) {
+			return false
+		}
+		return hosts[i].ID < hosts[j].ID
+	})
+}
+
+// printTable prints the hosts as a table of the selected columns. A non-empty
+// profile adds a leading PROFILE column.
+func (v listView) printTable(w *tabwriter.Writer, profile string, hosts []ProxyHost) {
+	for _, host := range hosts {
+		values := make([]string, 0, len(v.Columns)+1)
+		if profile != "" {
+			values = append(values, profile)
+		}
+		for _, column := range v.Columns {
+			values = append(values, listColumns[column].Value(host))
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+}
+
+// printHeader prints the header of the table of printTable
+func (v listView) printHeader(w *tabwriter.Writer, withProfile bool) {
+	headers := make([]string, 0, len(v.Columns)+1)
+	if withProfile {
+		headers = append(headers, "PROFILE")
+	}
+	for _, column := range v.Columns {
+		headers = append(headers, listColumns[column].Header)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+}
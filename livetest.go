@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// parseResolveOverrides parses --resolve values of the form host:ip
+func parseResolveOverrides(values []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, value := range values {
+		host, ip, ok := strings.Cut(value, ":")
+		ip = strings.Trim(ip, "[]")
+		if !ok || host == "" || net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid resolve override %q, expected host:ip", value)
+		}
+		overrides[strings.ToLower(host)] = ip
+	}
+	return overrides, nil
+}
+
+// newLiveTestClient builds the HTTP client for requests to the proxied sites.
+// Hosts in overrides are connected to at the given IP, while the URL still
+// determines the Host header and TLS server name, like curl's --resolve.
+func newLiveTestClient(overrides map[string]string, timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if ip, ok := overrides[strings.ToLower(host)]; ok {
+			addr = net.JoinHostPort(ip, port)
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// Report redirects as they are instead of following them
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// liveTestResult is the outcome of a request to one domain of a proxy host
+type liveTestResult struct {
+	URL      string
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// testProxyHostLive requests path on every concrete domain of a host. Hosts
+// with a certificate are tested over HTTPS, all others over HTTP.
+func testProxyHostLive(client *http.Client, host ProxyHost, path string) []liveTestResult {
+	scheme := "http"
+	if host.CertificateID != 0 {
+		scheme = "https"
+	}
+
+	var results []liveTestResult
+	for _, domain := range host.DomainNames {
+		// Wildcards have no single name to request
+		if strings.HasPrefix(domain, "*.") {
+			continue
+		}
+
+		result := liveTestResult{URL: scheme + "://" + domain + path}
+		start := time.Now()
+		resp, err := client.Get(result.URL)
+		result.Duration = time.Since(start)
+		if err != nil {
+			result.Err = err
+		} else {
+			result.Status = resp.StatusCode
+			resp.Body.Close()
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+var testCmd = &cobra.Command{
+	Use:          "test",
+	Short:        "Send live HTTP requests to the domains of a proxy host",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		id, _ := cmd.Flags().GetInt("id")
+		path, _ := cmd.Flags().GetString("path")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		resolveValues, _ := cmd.Flags().GetStringArray("resolve")
+		if id == 0 {
+			return fmt.Errorf("id is required")
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		overrides, err := parseResolveOverrides(resolveValues)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(id)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy host: %w", err)
+		}
+
+		results := testProxyHostLive(newLiveTestClient(overrides, timeout), *host, path)
+		if len(results) == 0 {
+			return fmt.Errorf("proxy host %d has no domain that can be tested", id)
+		}
+
+		failures := 0
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "URL\tSTATUS\tTIME\tERROR")
+		for _, result := range results {
+			status, problem := "-", "-"
+			if result.Err != nil {
+				problem = result.Err.Error()
+			} else {
+				status = fmt.Sprint(result.Status)
+			}
+			if result.Err != nil || result.Status >= 500 {
+				failures++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.URL, status, result.Duration.Round(time.Millisecond), problem)
+		}
+		w.Flush()
+
+		if failures > 0 {
+			return fmt.Errorf("%d of %d requests failed", failures, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	testCmd.Flags().Int("id", 0, "ID of the proxy host to test")
+	testCmd.Flags().String("path", "/", "Path to request on every domain")
+	testCmd.Flags().Duration("timeout", 10*time.Second, "Timeout of each request")
+	testCmd.Flags().StringArray("resolve", nil, "Connect to a domain at a fixed IP as host:ip, keeping the Host header and SNI (repeatable)")
+
+	rootCmd.AddCommand(testCmd)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// reorderLocations returns the locations in the given order. The order must
+// be a permutation of all location indices.
+func reorderLocations(locations []Location, order []int) ([]Location, error) {
+	if len(order) != len(locations) {
+		return nil, fmt.Errorf("order has %d indices but the host has %d locations", len(order), len(locations))
+	}
+
+	seen := make(map[int]bool, len(order))
+	reordered := make([]Location, 0, len(order))
+	for _, index := range order {
+		if index < 0 || index >= len(locations) {
+			return nil, fmt.Errorf("location index %d out of range 0-%d", index, len(locations)-1)
+		}
+		if seen[index] {
+			return nil, fmt.Errorf("location index %d given more than once", index)
+		}
+		seen[index] = true
+		reordered = append(reordered, locations[index])
+	}
+
+	return reordered, nil
+}
+
+// parseIndexList parses a comma separated list of integers
+func parseIndexList(value string) ([]int, error) {
+	var indices []int
+	for _, part := range strings.Split(value, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q", part)
+		}
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+// parseLocation parses a custom location given as path=scheme://host:port.
+// Without a port, the default port of the scheme is used.
+func parseLocation(value string) (Location, error) {
+	path, target, ok := strings.Cut(value, "=")
+	if !ok || !strings.HasPrefix(path, "/") {
+		return Location{}, fmt.Errorf("invalid location %q, expected /path=http://host:port", value)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" || (u.Path != "" && u.Path != "/") {
+		return Location{}, fmt.Errorf("invalid location target %q, expected http://host:port", target)
+	}
+
+	location := Location{
+		Path:          path,
+		ForwardScheme: u.Scheme,
+		ForwardHost:   u.Hostname(),
+	}
+	switch u.Scheme {
+	case "http":
+		location.ForwardPort = 80
+	case "https":
+		location.ForwardPort = 443
+	default:
+		return Location{}, fmt.Errorf("invalid location scheme %q, expected http or https", u.Scheme)
+	}
+	if u.Port() != "" {
+		if location.ForwardPort, err = strconv.Atoi(u.Port()); err != nil {
+			return Location{}, fmt.Errorf("invalid location port %q", u.Port())
+		}
+	}
+
+	return location, nil
+}
+
+// readLocationsFile reads a JSON array of custom locations
+func readLocationsFile(path string) ([]Location, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locations file: %w", err)
+	}
+
+	var locations []Location
+	if err := json.Unmarshal(content, &locations); err != nil {
+		return nil, fmt.Errorf("failed to parse locations file %s: %w", path, err)
+	}
+	for i, location := range locations {
+		if !strings.HasPrefix(location.Path, "/") || location.ForwardHost == "" || location.ForwardPort == 0 {
+			return nil, fmt.Errorf("location %d in %s needs a path starting with /, forward_host and forward_port", i, path)
+		}
+	}
+	return locations, nil
+}
+
+// addLocationFlags registers the flags that set the custom locations of a host
+func addLocationFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("location", nil, "Custom location as /path=http://host:port (repeatable)")
+	cmd.Flags().String("locations-file", "", "JSON file with an array of custom locations")
+	cmd.MarkFlagsMutuallyExclusive("location", "locations-file")
+}
+
+// applyLocationFlags replaces the custom locations of the host when one of
+// the location flags was given
+func applyLocationFlags(cmd *cobra.Command, host *ProxyHost) error {
+	flags := cmd.Flags()
+
+	if file, _ := flags.GetString("locations-file"); file != "" {
+		locations, err := readLocationsFile(file)
+		if err != nil {
+			return err
+		}
+		host.Locations = locations
+		return nil
+	}
+
+	if !flags.Changed("location") {
+		return nil
+	}
+	values, _ := flags.GetStringArray("location")
+	locations := make([]Location, 0, len(values))
+	for _, value := range values {
+		location, err := parseLocation(value)
+		if err != nil {
+			return err
+		}
+		locations = append(locations, location)
+	}
+	host.Locations = locations
+	return nil
+}
+
+var locationCmd = &cobra.Command{
+	Use:   "location",
+	Short: "Manage custom locations of proxy hosts",
+}
+
+var locationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the custom locations of a proxy host",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		hostID, _ := cmd.Flags().GetInt("host-id")
+		if hostID == 0 {
+			return fmt.Errorf("host-id is required")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(hostID)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy host: %w", err)
+		}
+
+		if len(host.Locations) == 0 {
+			fmt.Fprintf(out, "Proxy host %d has no custom locations\n", host.ID)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "INDEX\tPATH\tFORWARD\tADVANCED CONFIG")
+		for i, location := range host.Locations {
+			advanced := "no"
+			if strings.TrimSpace(location.AdvancedConfig) != "" {
+				advanced = "yes"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s://%s:%d\t%s\n", i, location.Path, location.ForwardScheme, location.ForwardHost, location.ForwardPort, advanced)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var locationReorderCmd = &cobra.Command{
+	Use:   "reorder",
+	Short: "Change the order of the custom locations of a proxy host",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		hostID, _ := cmd.Flags().GetInt("host-id")
+		orderValue, _ := cmd.Flags().GetString("order")
+		if hostID == 0 || orderValue == "" {
+			return fmt.Errorf("host-id and order are required")
+		}
+
+		order, err := parseIndexList(orderValue)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(hostID)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy host: %w", err)
+		}
+
+		if host.Locations, err = reorderLocations(host.Locations, order); err != nil {
+			return err
+		}
+
+		updatedHost, err := client.UpdateProxyHost(hostID, *host)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully reordered locations of proxy host %d:\n", updatedHost.ID)
+		for i, location := range updatedHost.Locations {
+			fmt.Fprintf(out, "%d: %s\n", i, location.Path)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	locationListCmd.Flags().Int("host-id", 0, "ID of the proxy host")
+
+	locationReorderCmd.Flags().Int("host-id", 0, "ID of the proxy host")
+	locationReorderCmd.Flags().String("order", "", "New order as comma separated current indices, e.g. 2,0,1")
+
+	locationCmd.AddCommand(locationListCmd)
+	locationCmd.AddCommand(locationReorderCmd)
+	rootCmd.AddCommand(locationCmd)
+}
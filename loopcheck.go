@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// npmPorts returns the ports NPM listens on: HTTP and HTTPS for the proxy
+// hosts and the admin port of the API URL
+func npmPorts(apiURL string) map[int]bool {
+	ports := map[int]bool{80: true, 443: true, 81: true}
+
+	if u, err := url.Parse(apiURL); err == nil {
+		if port, err := strconv.Atoi(u.Port()); err == nil {
+			ports[port] = true
+		}
+	}
+	return ports
+}
+
+// resolveAddresses returns the IP addresses of a host name or IP. Names that
+// can't be resolved are returned as they are.
+func resolveAddresses(host string) []string {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}
+	}
+
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return []string{strings.ToLower(host)}
+	}
+	return addrs
+}
+
+// checkForwardLoop reports whether the forward target of a host is the NPM
+// instance itself, which would make nginx proxy requests back to itself
+func checkForwardLoop(host ProxyHost, apiURL string) error {
+	if !npmPorts(apiURL)[host.ForwardPort] {
+		return nil
+	}
+
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+
+	npmAddrs := make(map[string]bool)
+	for _, addr := range resolveAddresses(u.Hostname()) {
+		npmAddrs[addr] = true
+	}
+
+	for _, addr := range resolveAddresses(host.ForwardHost) {
+		// Loopback addresses point at NPM when nginx resolves them
+		loopback := false
+		if ip := net.ParseIP(addr); ip != nil {
+			loopback = ip.IsLoopback()
+		}
+
+		if npmAddrs[addr] || loopback {
+			return fmt.Errorf("forward target %s:%d is the NPM instance itself (%s): nginx would proxy every request back to itself in a loop until it fails",
+				host.ForwardHost, host.ForwardPort, u.Host)
+		}
+	}
+
+	return nil
+}
+
+// applyForwardLoopCheck refuses forward targets that point back at NPM
+// unless --force was given, in which case it only warns
+func applyForwardLoopCheck(cmd *cobra.Command, host ProxyHost) error {
+	err := checkForwardLoop(host, apiURL)
+	if err == nil {
+		return nil
+	}
+
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+	return fmt.Errorf("%w. Use --force if this is intended", err)
+}
@@ -2,34 +2,140 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	apiURL   string
-	username string
-	password string
-	token    string
+	apiURL          string
+	username        string
+	password        string
+	token           string
+	maxResponseSize int64
+	output          string
+	identityField   string
+	aliasValues     []string
 )
 
+// out receives the primary output of all commands, see --output and --out-file
+var out io.Writer = os.Stdout
+
+// outFile is the path given with --out-file
+var outFile string
+
+// outFileHandle is the open --out-file, closed by closeOutFile
+var outFileHandle *os.File
+
+// closeOutFile closes the --out-file if one is open
+func closeOutFile() error {
+	if outFileHandle == nil {
+		return nil
+	}
+	f := outFileHandle
+	outFileHandle = nil
+	out = os.Stdout
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+	return nil
+}
+
+// defaultMaxResponseSize is the largest response body read from the API by default
+const defaultMaxResponseSize = 4 << 20
+
 // APIClient represents the Nginx Proxy Manager API client
 type APIClient struct {
-	BaseURL    string
-	HTTPClient *http.Client
-	Token      string
+	BaseURL         string
+	HTTPClient      *http.Client
+	IdentityField   string
+	MaxResponseSize int64
+
+	*clientSession
+
+	// ctx limits the requests made through this client, see WithContext
+	ctx context.Context
+}
+
+// clientSession holds the state shared by a client and the copies made with
+// WithContext
+type clientSession struct {
+	Token string
+
+	// Credentials of the last successful authentication, used to get a new
+	// token when the API rejects the current one
+	username string
+	password string
+
+	cache map[string]cachedResponse
+
+	// mu guards the token, credentials and cache, which are shared by
+	// concurrent requests of a single command
+	mu sync.Mutex
+	// authMu serializes token renewals, so concurrent requests that get a 401
+	// for the same token authenticate only once
+	authMu sync.Mutex
+}
+
+var (
+	// ErrUnauthorized is returned when the API rejects the token and it can't be renewed
+	ErrUnauthorized = errors.New("authentication rejected, check your credentials or token")
+
+	// ErrPermissionDenied is returned when the account is authenticated but not allowed to do something
+	ErrPermissionDenied = errors.New("your account lacks permission for this operation")
+)
+
+// exitCodeError makes the CLI exit with a specific status instead of 1
+type exitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// cachedResponse holds a response body together with its cache validators
+type cachedResponse struct {
+	ETag         string
+	LastModified string
+	Body         []byte
 }
 
 // AuthRequest represents the authentication request structure
 type AuthRequest struct {
 	Identity string `json:"identity"`
 	Password string `json:"password"`
+
+	// IdentityField overrides the JSON name of Identity for external auth providers
+	IdentityField string `json:"-"`
+}
+
+// MarshalJSON sends Identity under IdentityField when it is set
+func (r AuthRequest) MarshalJSON() ([]byte, error) {
+	field := r.IdentityField
+	if field == "" {
+		field = "identity"
+	}
+	return json.Marshal(map[string]string{
+		field:      r.Identity,
+		"password": r.Password,
+	})
 }
 
 // AuthResponse represents the authentication response structure
@@ -49,10 +155,156 @@ type ProxyHost struct {
 	SslForced         bool     `json:"ssl_forced"`
 	CachingEnabled    bool     `json:"caching_enabled"`
 	BlockExploits     bool     `json:"block_exploits"`
+	AllowWebsocketUpgrade bool `json:"allow_websocket_upgrade"`
+	HTTP2Support      bool     `json:"http2_support"`
+	HSTSEnabled       bool     `json:"hsts_enabled"`
+	HSTSSubdomains    bool     `json:"hsts_subdomains"`
 	AdvancedConfig    string   `json:"advanced_config"`
 	Enabled           bool     `json:"enabled"`
 	CreatedOn         string   `json:"created_on"`
 	ModifiedOn        string   `json:"modified_on"`
+	Meta              ProxyHostMeta `json:"meta"`
+	Locations         []Location `json:"locations"`
+
+	// Certificate, Owner and AccessList are only set by ListProxyHostsExpanded
+	// and GetProxyHostDetails, and are never sent back to the API
+	Certificate       *Certificate `json:"certificate,omitempty"`
+	Owner             *User        `json:"owner,omitempty"`
+	AccessList        *AccessList  `json:"access_list,omitempty"`
+
+	// Extra holds raw fields merged over the modeled ones when encoding, see --extra-json
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// fieldAliases maps NPM proxy host JSON fields to the names used by an NPM fork, see --field-alias
+var fieldAliases map[string]string
+
+// UnmarshalJSON decodes a proxy host. Fields that an NPM fork sends under a
+// different name are read from their alias when the NPM name is missing.
+func (h *ProxyHost) UnmarshalJSON(data []byte) error {
+	type plainProxyHost ProxyHost
+
+	if len(fieldAliases) > 0 {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+
+		for npmField, forkField := range fieldAliases {
+			value, ok := fields[forkField]
+			if _, exists := fields[npmField]; ok && !exists {
+				fields[npmField] = value
+			}
+		}
+
+		var err error
+		if data, err = json.Marshal(fields); err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(data, (*plainProxyHost)(h))
+}
+
+// MarshalJSON encodes a proxy host. Extra fields replace modeled fields of the same name.
+func (h ProxyHost) MarshalJSON() ([]byte, error) {
+	type plainProxyHost ProxyHost
+
+	data, err := json.Marshal(plainProxyHost(h))
+	if err != nil || len(h.Extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range h.Extra {
+		fields[name] = value
+	}
+
+	return json.Marshal(fields)
+}
+
+// parseFieldAliases parses npm_field=fork_field pairs given with --field-alias
+func parseFieldAliases(values []string) (map[string]string, error) {
+	known := proxyHostFields()
+
+	aliases := make(map[string]string, len(values))
+	for _, value := range values {
+		npmField, forkField, ok := strings.Cut(value, "=")
+		if !ok || npmField == "" || forkField == "" {
+			return nil, fmt.Errorf("invalid field alias %q, expected npm_field=fork_field", value)
+		}
+		if !known[npmField] {
+			return nil, fmt.Errorf("invalid field alias %q, unknown proxy host field %q", value, npmField)
+		}
+		aliases[npmField] = forkField
+	}
+	return aliases, nil
+}
+
+// Location represents a custom location of a proxy host
+type Location struct {
+	Path           string `json:"path"`
+	ForwardScheme  string `json:"forward_scheme"`
+	ForwardHost    string `json:"forward_host"`
+	ForwardPort    int    `json:"forward_port"`
+	AdvancedConfig string `json:"advanced_config"`
+}
+
+// ProxyHostMeta holds the status information NPM reports for a proxy host.
+// NPM keeps other settings in meta as well, like letsencrypt_email or
+// dns_challenge, which are kept as received and sent back unchanged.
+type ProxyHostMeta struct {
+	NginxOnline bool   `json:"nginx_online,omitempty"`
+	NginxErr    string `json:"nginx_err,omitempty"`
+
+	raw map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes the status fields and keeps all fields as received
+func (m *ProxyHostMeta) UnmarshalJSON(data []byte) error {
+	type plainMeta ProxyHostMeta
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var plain plainMeta
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return err
+	}
+
+	*m = ProxyHostMeta(plain)
+	m.raw = raw
+	return nil
+}
+
+// MarshalJSON encodes the fields as received, with the status fields of m
+func (m ProxyHostMeta) MarshalJSON() ([]byte, error) {
+	type plainMeta ProxyHostMeta
+
+	data, err := json.Marshal(plainMeta(m))
+	if err != nil || len(m.raw) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	merged := make(map[string]json.RawMessage, len(m.raw)+len(fields))
+	for name, value := range m.raw {
+		merged[name] = value
+	}
+	delete(merged, "nginx_online")
+	delete(merged, "nginx_err")
+	for name, value := range fields {
+		merged[name] = value
+	}
+
+	return json.Marshal(merged)
 }
 
 // NewAPIClient creates a new API client
@@ -60,16 +312,94 @@ func NewAPIClient(baseURL string) *APIClient {
 	return &APIClient{
 		BaseURL: baseURL,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: apiTransport(),
 		},
+		MaxResponseSize: defaultMaxResponseSize,
+		clientSession:   &clientSession{cache: make(map[string]cachedResponse)},
+	}
+}
+
+// newClient creates an API client for the given instance configured from the global flags
+func newClient(baseURL, token string) *APIClient {
+	client := NewAPIClient(baseURL)
+	client.MaxResponseSize = maxResponseSize
+	client.IdentityField = identityField
+	client.Token = token
+	return client
+}
+
+// newAuthenticatedClient creates an API client from the global flags and authenticates it
+func newAuthenticatedClient() (*APIClient, error) {
+	client := newClient(apiURL, token)
+
+	if err := client.Authenticate(username, password); err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	return client, nil
+}
+
+// limitResponseBody buffers the response body, refusing bodies larger than
+// MaxResponseSize so a misbehaving endpoint can't exhaust memory
+func (c *APIClient) limitResponseBody(resp *http.Response) error {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, c.MaxResponseSize+1))
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if int64(len(data)) > c.MaxResponseSize {
+		return fmt.Errorf("response too large (more than %d bytes, status: %d), body starts with: %q",
+			c.MaxResponseSize, resp.StatusCode, bodyPrefix(data))
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return nil
+}
+
+// bodyPrefix returns the first bytes of a response body for error messages
+func bodyPrefix(data []byte) string {
+	const max = 200
+	if len(data) > max {
+		return string(data[:max]) + "..."
+	}
+	return string(data)
+}
+
+// decodeJSON decodes a JSON response body. When the body isn't JSON, for
+// example an HTML error page, the error includes the start of the body.
+func decodeJSON(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("response is not JSON (%v), body starts with: %q", err, bodyPrefix(data))
+		}
+		return err
 	}
+
+	return nil
 }
 
-// Authenticate performs authentication and stores the token
+// Authenticate performs authentication and stores the token. When the client
+// already has a pre-issued token, it is validated instead of posting credentials.
 func (c *APIClient) Authenticate(username, password string) error {
+	c.mu.Lock()
+	preIssued := c.Token != "" && c.username == ""
+	c.mu.Unlock()
+	if preIssued {
+		return c.validateToken()
+	}
+
 	authReq := AuthRequest{
-		Identity: username,
-		Password: password,
+		Identity:      username,
+		Password:      password,
+		IdentityField: c.IdentityField,
 	}
 
 	jsonData, err := json.Marshal(authReq)
@@ -77,9 +407,19 @@ func (c *APIClient) Authenticate(username, password string) error {
 		return fmt.Errorf("failed to marshal auth request: %w", err)
 	}
 
-	resp, err := c.HTTPClient.Post(c.BaseURL+"/tokens", "application/json", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.requestContext(), "POST", c.BaseURL+"/tokens", bytes.NewReader(jsonData))
 	if err != nil {
-		return fmt.Errorf("failed to make auth request: %w", err)
+		return fmt.Errorf("failed to create auth request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	printCurl(req, jsonData)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make auth request: %w", explainTLSError(err))
+	}
+	if err := c.limitResponseBody(resp); err != nil {
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -88,49 +428,288 @@ func (c *APIClient) Authenticate(username, password string) error {
 	}
 
 	var authResp AuthResponse
-	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+	if err := decodeJSON(resp.Body, &authResp); err != nil {
 		return fmt.Errorf("failed to decode auth response: %w", err)
 	}
 
+	c.mu.Lock()
 	c.Token = authResp.Token
+	c.username = username
+	c.password = password
+	c.mu.Unlock()
+	return nil
+}
+
+// currentToken returns the token and whether the client can renew it
+func (c *APIClient) currentToken() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Token, c.username != ""
+}
+
+// renewToken authenticates again after the API rejected staleToken. When
+// another request already replaced that token, the new one is used as is.
+func (c *APIClient) renewToken(staleToken string) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.mu.Lock()
+	renewed := c.Token != staleToken
+	username, password := c.username, c.password
+	c.mu.Unlock()
+	if renewed {
+		return nil
+	}
+
+	return c.Authenticate(username, password)
+}
+
+// validateToken checks the current token with a probe request
+func (c *APIClient) validateToken() error {
+	resp, err := c.makeAuthenticatedRequest("GET", "/users/me", nil)
+	if err != nil {
+		return fmt.Errorf("failed to validate token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token rejected with status: %d", resp.StatusCode)
+	}
+
 	return nil
 }
 
 // makeAuthenticatedRequest makes an authenticated request to the API
 func (c *APIClient) makeAuthenticatedRequest(method, endpoint string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, c.BaseURL+endpoint, body)
+	return c.makeAuthenticatedRequestWithHeaders(method, endpoint, body, nil)
+}
+
+// makeAuthenticatedRequestWithHeaders makes an authenticated request to the API,
+// replacing or adding the given headers after the defaults are set.
+//
+// A 401 response means the token is no longer valid: when the client knows the
+// credentials it authenticates again and repeats the request once. A 403
+// response means the account lacks permission, which a new token won't fix,
+// so it is returned as ErrPermissionDenied right away.
+func (c *APIClient) makeAuthenticatedRequestWithHeaders(method, endpoint string, body io.Reader, header http.Header) (*http.Response, error) {
+	resp, err := c.sendAuthenticatedRequest(method, endpoint, body, header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %w", method, endpoint, ErrUnauthorized)
+	case http.StatusForbidden:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %w", method, endpoint, ErrPermissionDenied)
+	}
+
+	return resp, nil
+}
+
+// sendAuthenticatedRequest is makeAuthenticatedRequestWithHeaders without the
+// handling of 401 and 403 responses: after the token renewal, the response is
+// returned as is whatever its status, for the raw command.
+func (c *APIClient) sendAuthenticatedRequest(method, endpoint string, body io.Reader, header http.Header) (*http.Response, error) {
+	// Buffer the body so the request can be repeated
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	token, renewable := c.currentToken()
+	resp, err := c.doAuthenticatedRequest(method, endpoint, token, payload, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && renewable {
+		resp.Body.Close()
+		if err := c.renewToken(token); err != nil {
+			return nil, fmt.Errorf("failed to renew token: %w", err)
+		}
+		token, _ = c.currentToken()
+		if resp, err = c.doAuthenticatedRequest(method, endpoint, token, payload, header); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// doAuthenticatedRequest sends a single request with the given token
+func (c *APIClient) doAuthenticatedRequest(method, endpoint, token string, payload []byte, header http.Header) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(c.requestContext(), method, c.BaseURL+endpoint, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Authorization", "Bearer "+token)
+	for key, values := range header {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	printCurl(req, payload)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, explainTLSError(err)
+	}
+	if err := c.limitResponseBody(resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// getWithCache performs a conditional GET request. When a previous response for
+// the endpoint carried an ETag or Last-Modified validator, it is sent back and a
+// 304 Not Modified answer reuses the cached body. Responses without validators
+// are not cached, so servers that don't send them behave like a plain GET.
+func (c *APIClient) getWithCache(endpoint string) (int, []byte, error) {
+	header := http.Header{}
+	c.mu.Lock()
+	cached, ok := c.cache[endpoint]
+	c.mu.Unlock()
+	if ok {
+		if cached.ETag != "" {
+			header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.makeAuthenticatedRequestWithHeaders("GET", endpoint, nil, header)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		return http.StatusOK, cached.Body, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-	return c.HTTPClient.Do(req)
+	if resp.StatusCode == http.StatusOK {
+		etag := resp.Header.Get("ETag")
+		lastModified := resp.Header.Get("Last-Modified")
+		c.mu.Lock()
+		if etag != "" || lastModified != "" {
+			c.cache[endpoint] = cachedResponse{ETag: etag, LastModified: lastModified, Body: body}
+		} else {
+			delete(c.cache, endpoint)
+		}
+		c.mu.Unlock()
+	}
+
+	return resp.StatusCode, body, nil
 }
 
 // ListProxyHosts lists all proxy hosts
 func (c *APIClient) ListProxyHosts() ([]ProxyHost, error) {
-	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/proxy-hosts", nil)
+	return c.listProxyHosts("/nginx/proxy-hosts")
+}
+
+// ListProxyHostsExpanded lists all proxy hosts with their certificate, owner and access list
+func (c *APIClient) ListProxyHostsExpanded() ([]ProxyHost, error) {
+	return c.listProxyHosts("/nginx/proxy-hosts?expand=owner,certificate,access_list")
+}
+
+func (c *APIClient) listProxyHosts(endpoint string) ([]ProxyHost, error) {
+	statusCode, body, err := c.getWithCache(endpoint)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list proxy hosts, status: %d", resp.StatusCode)
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list proxy hosts, status: %d", statusCode)
 	}
 
 	var hosts []ProxyHost
-	if err := json.NewDecoder(resp.Body).Decode(&hosts); err != nil {
+	if err := decodeJSON(bytes.NewReader(body), &hosts); err != nil {
 		return nil, fmt.Errorf("failed to decode proxy hosts: %w", err)
 	}
 
 	return hosts, nil
 }
 
+// GetProxyHost fetches a single proxy host by ID
+func (c *APIClient) GetProxyHost(id int) (*ProxyHost, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/proxy-hosts/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("proxy host %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get proxy host, status: %d", resp.StatusCode)
+	}
+
+	var host ProxyHost
+	if err := decodeJSON(resp.Body, &host); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy host: %w", err)
+	}
+
+	return &host, nil
+}
+
+// WaitForOnline polls a proxy host until nginx reports it online or the timeout expires
+func (c *APIClient) WaitForOnline(id int, timeout time.Duration) (*ProxyHost, error) {
+	deadline := time.Now().Add(timeout)
+	delay := 500 * time.Millisecond
+
+	for {
+		host, err := c.GetProxyHost(id)
+		if err != nil {
+			return nil, err
+		}
+		if host.Meta.NginxOnline {
+			return host, nil
+		}
+
+		if time.Now().Add(delay).After(deadline) {
+			if host.Meta.NginxErr != "" {
+				return host, fmt.Errorf("proxy host %d did not come online within %s: %s", id, timeout, host.Meta.NginxErr)
+			}
+			return host, fmt.Errorf("proxy host %d did not come online within %s", id, timeout)
+		}
+
+		time.Sleep(delay)
+		if delay *= 2; delay > 5*time.Second {
+			delay = 5 * time.Second
+		}
+	}
+}
+
 // CreateProxyHost creates a new proxy host
 func (c *APIClient) CreateProxyHost(host ProxyHost) (*ProxyHost, error) {
+	host.Certificate, host.Owner, host.AccessList = nil, nil, nil
+	// Always send locations, an empty list is how they are removed
+	if host.Locations == nil {
+		host.Locations = []Location{}
+	}
 	jsonData, err := json.Marshal(host)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal proxy host: %w", err)
@@ -148,13 +727,44 @@ func (c *APIClient) CreateProxyHost(host ProxyHost) (*ProxyHost, error) {
 	}
 
 	var createdHost ProxyHost
-	if err := json.NewDecoder(resp.Body).Decode(&createdHost); err != nil {
+	if err := decodeJSON(resp.Body, &createdHost); err != nil {
 		return nil, fmt.Errorf("failed to decode created proxy host: %w", err)
 	}
 
 	return &createdHost, nil
 }
 
+// UpdateProxyHost updates an existing proxy host
+func (c *APIClient) UpdateProxyHost(id int, host ProxyHost) (*ProxyHost, error) {
+	host.Certificate, host.Owner, host.AccessList = nil, nil, nil
+	// Always send locations, an empty list is how they are removed
+	if host.Locations == nil {
+		host.Locations = []Location{}
+	}
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal proxy host: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/nginx/proxy-hosts/%d", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update proxy host, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var updatedHost ProxyHost
+	if err := decodeJSON(resp.Body, &updatedHost); err != nil {
+		return nil, fmt.Errorf("failed to decode updated proxy host: %w", err)
+	}
+
+	return &updatedHost, nil
+}
+
 // DeleteProxyHost deletes a proxy host by ID
 func (c *APIClient) DeleteProxyHost(id int) error {
 	resp, err := c.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/nginx/proxy-hosts/%d", id), nil)
@@ -170,105 +780,658 @@ func (c *APIClient) DeleteProxyHost(id int) error {
 	return nil
 }
 
+// findProxyHostsByDomain returns all hosts that serve the given domain name
+func findProxyHostsByDomain(hosts []ProxyHost, domain string) []ProxyHost {
+	var matches []ProxyHost
+	for _, host := range hosts {
+		for _, name := range host.DomainNames {
+			if strings.EqualFold(name, domain) {
+				matches = append(matches, host)
+				break
+			}
+		}
+	}
+	return matches
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "nginxproxymanager-cli",
 	Short: "A CLI tool for managing Nginx Proxy Manager",
 	Long:  `A command line interface for interacting with Nginx Proxy Manager API.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if preferIPv4 && preferIPv6 {
+			return fmt.Errorf("--prefer-ipv4 and --prefer-ipv6 cannot be used together")
+		}
+		if maxResponseSize <= 0 {
+			return fmt.Errorf("--max-response-size must be a positive number of bytes, got %d", maxResponseSize)
+		}
+
+		var err error
+		if fieldAliases, err = parseFieldAliases(aliasValues); err != nil {
+			return err
+		}
+
+		if tlsMinVersion, err = parseTLSVersion(tlsMinVersionName); err != nil {
+			return err
+		}
+
+		if err := setupSessionTransport(); err != nil {
+			return err
+		}
+
+		if err := applyProfile(cmd); err != nil {
+			return err
+		}
+
+		if credentialStore, err = resolveCredentialStore(credentialStore); err != nil {
+			return err
+		}
+		if err := applyStoredCredentials(); err != nil {
+			return err
+		}
+
+		switch output {
+		case "text", "json":
+			out = os.Stdout
+		case "none":
+			// Only the exit code and errors on stderr remain
+			out = io.Discard
+		default:
+			return fmt.Errorf("invalid output format %q, expected text, json or none", output)
+		}
+
+		if outFile != "" && output != "none" {
+			f, err := os.Create(outFile)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			outFileHandle = f
+			out = f
+		}
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return closeOutFile()
+	},
 }
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all proxy hosts",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client := NewAPIClient(apiURL)
-		
-		if err := client.Authenticate(username, password); err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+		watch, _ := cmd.Flags().GetDuration("watch")
+		count, _ := cmd.Flags().GetBool("count")
+		summary, _ := cmd.Flags().GetBool("summary")
+
+		filters, err := listFilters(cmd)
+		if err != nil {
+			return err
 		}
 
-		hosts, err := client.ListProxyHosts()
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		columns, _ := cmd.Flags().GetString("columns")
+		view, err := parseListView(sortBy, columns)
 		if err != nil {
-			return fmt.Errorf("failed to list proxy hosts: %w", err)
+			return err
 		}
 
-		fmt.Printf("Found %d proxy hosts:\n\n", len(hosts))
-		for _, host := range hosts {
-			fmt.Printf("ID: %d\n", host.ID)
-			fmt.Printf("Domain Names: %v\n", host.DomainNames)
-			fmt.Printf("Forward: %s://%s:%d\n", host.ForwardScheme, host.ForwardHost, host.ForwardPort)
-			fmt.Printf("Enabled: %t\n", host.Enabled)
-			fmt.Printf("SSL Forced: %t\n", host.SslForced)
-			fmt.Println("---")
+		allProfiles, err := checkAllProfiles(cmd)
+		if err != nil {
+			return err
+		}
+		if allProfiles {
+			if watch > 0 {
+				return fmt.Errorf("--watch cannot be used with --all-profiles")
+			}
+			return listAllProfiles(filters, view, count, summary)
 		}
 
-		return nil
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		for {
+			hosts, err := client.ListProxyHostsExpanded()
+			if err != nil {
+				return fmt.Errorf("failed to list proxy hosts: %w", err)
+			}
+
+			hosts = filterProxyHosts(hosts, filters)
+			view.sort(hosts)
+
+			if watch > 0 && output == "text" {
+				fmt.Fprintf(out, "[%s] ", time.Now().Format(time.TimeOnly))
+			}
+
+			switch {
+			case count && output == "json":
+				err = writeJSON(map[string]int{"count": len(hosts)})
+			case count:
+				fmt.Fprintln(out, len(hosts))
+			case output == "json" && summary:
+				err = writeJSON(map[string]any{"items": hosts, "summary": summarizeProxyHosts(hosts)})
+			case output == "json":
+				err = writeJSON(hosts)
+			case len(view.Columns) > 0:
+				w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				view.printHeader(w, false)
+				view.printTable(w, "", hosts)
+				w.Flush()
+				if summary {
+					printListSummary(summarizeProxyHosts(hosts))
+				}
+			default:
+				printProxyHosts(hosts)
+				if summary {
+					printListSummary(summarizeProxyHosts(hosts))
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			if watch <= 0 {
+				return nil
+			}
+			time.Sleep(watch)
+		}
 	},
 }
 
+// writeJSON writes v as indented JSON to the command output
+func writeJSON(v any) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	_, err = fmt.Fprintln(out, string(jsonData))
+	return err
+}
+
+// listSummary holds aggregate counts of listed proxy hosts
+type listSummary struct {
+	Total     int `json:"total"`
+	Enabled   int `json:"enabled"`
+	SslForced int `json:"ssl_forced"`
+	Offline   int `json:"offline"`
+}
+
+// summarizeProxyHosts counts the given proxy hosts. Offline counts enabled
+// hosts that nginx doesn't report online.
+func summarizeProxyHosts(hosts []ProxyHost) listSummary {
+	summary := listSummary{Total: len(hosts)}
+	for _, host := range hosts {
+		if host.Enabled {
+			summary.Enabled++
+			if !host.Meta.NginxOnline {
+				summary.Offline++
+			}
+		}
+		if host.SslForced {
+			summary.SslForced++
+		}
+	}
+	return summary
+}
+
+// printListSummary prints the summary footer of the list command
+func printListSummary(summary listSummary) {
+	fmt.Fprintf(out, "Total: %d, Enabled: %d, SSL Forced: %d, Offline: %d\n",
+		summary.Total, summary.Enabled, summary.SslForced, summary.Offline)
+}
+
+// printProxyHosts prints the list output for the given proxy hosts
+func printProxyHosts(hosts []ProxyHost) {
+	fmt.Fprintf(out, "Found %d proxy hosts:\n\n", len(hosts))
+	for _, host := range hosts {
+		printProxyHost(host)
+	}
+}
+
+// printProxyHost prints the list entry of a single proxy host
+func printProxyHost(host ProxyHost) {
+	fmt.Fprintf(out, "ID: %d\n", host.ID)
+	fmt.Fprintf(out, "Domain Names: %v\n", host.DomainNames)
+	fmt.Fprintf(out, "Forward: %s://%s:%d\n", host.ForwardScheme, host.ForwardHost, host.ForwardPort)
+	fmt.Fprintf(out, "Enabled: %t\n", host.Enabled)
+	fmt.Fprintf(out, "SSL: %s\n", sslStatus(host))
+	if host.CertificateID != 0 {
+		fmt.Fprintf(out, "Certificate: %s\n", certificateLabel(host))
+	}
+	if host.AccessListID != 0 {
+		fmt.Fprintf(out, "Access List: %s\n", accessListName(host))
+	}
+	if host.Owner != nil {
+		fmt.Fprintf(out, "Owner: %s\n", host.Owner.Name)
+	}
+	fmt.Fprintln(out, "---")
+}
+
+// listAllProfiles prints the proxy hosts of every configured profile, each
+// labeled with the profile it belongs to
+func listAllProfiles(filters []proxyHostFilter, view listView, count, summary bool) error {
+	results, err := fanOutProfiles(func(client *APIClient) ([]ProxyHost, error) {
+		hosts, err := client.ListProxyHostsExpanded()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+		hosts = filterProxyHosts(hosts, filters)
+		view.sort(hosts)
+		return hosts, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		outputs := make([]profileOutput, 0, len(results))
+		for _, result := range results {
+			profileOut := profileOutput{Profile: result.Profile}
+			switch {
+			case result.Err != nil:
+				profileOut.Error = result.Err.Error()
+			case count:
+				n := len(result.Items)
+				profileOut.Count = &n
+			default:
+				profileOut.Items = append([]ProxyHost{}, result.Items...)
+				if summary {
+					profileOut.Summary = summarizeProxyHosts(result.Items)
+				}
+			}
+			outputs = append(outputs, profileOut)
+		}
+		if err := writeJSON(outputs); err != nil {
+			return err
+		}
+		return reportProfileErrors(results)
+	}
+
+	total := 0
+	for _, result := range results {
+		total += len(result.Items)
+	}
+
+	switch {
+	case count:
+		for _, result := range results {
+			if result.Err == nil {
+				fmt.Fprintf(out, "%s: %d\n", result.Profile, len(result.Items))
+			}
+		}
+	case len(view.Columns) > 0:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		view.printHeader(w, true)
+		for _, result := range results {
+			view.printTable(w, result.Profile, result.Items)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintf(out, "Found %d proxy hosts on %d profiles:\n\n", total, len(results))
+		for _, result := range results {
+			for _, host := range result.Items {
+				fmt.Fprintf(out, "Profile: %s\n", result.Profile)
+				printProxyHost(host)
+			}
+		}
+	}
+	if summary && !count {
+		for _, result := range results {
+			if result.Err == nil {
+				fmt.Fprintf(out, "%s: ", result.Profile)
+				printListSummary(summarizeProxyHosts(result.Items))
+			}
+		}
+	}
+
+	return reportProfileErrors(results)
+}
+
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new proxy host",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get required parameters first
-		domainName, _ := cmd.Flags().GetString("domain")
-		forwardHost, _ := cmd.Flags().GetString("forward-host")
-		forwardPort, _ := cmd.Flags().GetInt("forward-port")
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
 		forwardScheme, _ := cmd.Flags().GetString("forward-scheme")
+		file, _ := cmd.Flags().GetString("file")
 
 		// Validate required parameters before authentication
-		if domainName == "" || forwardHost == "" || forwardPort == 0 {
-			return fmt.Errorf("domain, forward-host, and forward-port are required")
-		}
-
-		client := NewAPIClient(apiURL)
-		
-		if err := client.Authenticate(username, password); err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
 		}
 
 		host := ProxyHost{
-			DomainNames:   []string{domainName},
 			ForwardScheme: forwardScheme,
-			ForwardHost:   forwardHost,
-			ForwardPort:   forwardPort,
 			Enabled:       true,
 			BlockExploits: true,
 		}
+		if file != "" {
+			if host, err = readProxyHostFile(file, host); err != nil {
+				return err
+			}
+		}
+
+		// Flags override the values of the file
+		if len(domains) > 0 {
+			host.DomainNames = domains
+		}
+		if cmd.Flags().Changed("disabled") {
+			disabled, _ := cmd.Flags().GetBool("disabled")
+			host.Enabled = !disabled
+		}
+
+		hostHeaderChanged, err := applyProxyHostFlags(cmd, &host)
+		if err != nil {
+			return err
+		}
+		if len(host.DomainNames) == 0 || host.ForwardHost == "" || host.ForwardPort == 0 {
+			return fmt.Errorf("domain, forward-host, and forward-port are required")
+		}
+		if err := validateCreateSSLFlags(cmd, host.DomainNames); err != nil {
+			return err
+		}
+		// With --ssl the host is created without SSL, which is enabled once
+		// its certificate is issued
+		requestSSL, _ := cmd.Flags().GetBool("ssl")
+		var sslSettings hostSSLSettings
+		if requestSSL {
+			sslSettings = takeSSLSettings(&host)
+		}
+		if file != "" {
+			if err := validateProxyHost(host); err != nil {
+				return fmt.Errorf("invalid proxy host: %w", err)
+			}
+		}
+
+		waitForOnline, _ := cmd.Flags().GetBool("wait-for-online")
+		if !host.Enabled && waitForOnline {
+			return fmt.Errorf("a disabled proxy host never comes online, --disabled and --wait-for-online cannot be combined")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if err := resolveReferenceFlags(cmd, client, &host); err != nil {
+			return err
+		}
+		// Raw fields go last, so they win over every resolved flag
+		if err := applyExtraJSON(cmd, &host); err != nil {
+			return err
+		}
+		if waitForOnline && !host.Enabled {
+			return fmt.Errorf("a disabled proxy host never comes online, --extra-json disables it and --wait-for-online cannot be used")
+		}
+		if err := applyForwardLoopCheck(cmd, host); err != nil {
+			return err
+		}
+
+		// NPM fills in the account email in its UI, do the same
+		email, _ := cmd.Flags().GetString("letsencrypt-email")
+		if requestSSL && email == "" {
+			user, err := client.GetCurrentUser()
+			if err != nil {
+				return fmt.Errorf("no --letsencrypt-email given and the email of the current user is unknown: %w", err)
+			}
+			email = user.Email
+		}
+
+		replace, _ := cmd.Flags().GetBool("replace")
+		if err := applyDuplicateDomainCheck(cmd, client, host.DomainNames, replace); err != nil {
+			return err
+		}
+
+		var replaced *ProxyHost
+		var backupPath string
+		if replace {
+			yes, _ := cmd.Flags().GetBool("yes")
+			backupDir, _ := cmd.Flags().GetString("backup-before")
+			if replaced, backupPath, err = replaceProxyHost(client, host.DomainNames, yes, backupDir); err != nil {
+				return err
+			}
+		}
 
 		createdHost, err := client.CreateProxyHost(host)
 		if err != nil {
+			if replaced != nil {
+				return restoreReplacedProxyHost(client, *replaced, backupPath, err)
+			}
 			return fmt.Errorf("failed to create proxy host: %w", err)
 		}
 
-		fmt.Printf("Successfully created proxy host with ID: %d\n", createdHost.ID)
-		fmt.Printf("Domain: %v\n", createdHost.DomainNames)
-		fmt.Printf("Forward: %s://%s:%d\n", createdHost.ForwardScheme, createdHost.ForwardHost, createdHost.ForwardPort)
+		fmt.Fprintf(out, "Successfully created proxy host with ID: %d\n", createdHost.ID)
+		fmt.Fprintf(out, "Domain: %v\n", createdHost.DomainNames)
+		fmt.Fprintf(out, "Forward: %s://%s:%d\n", createdHost.ForwardScheme, createdHost.ForwardHost, createdHost.ForwardPort)
+		fmt.Fprintf(out, "Enabled: %t\n", createdHost.Enabled)
+		if hostHeaderChanged {
+			fmt.Fprintf(out, "Host Header: %s\n", hostHeaderSetting(createdHost.AdvancedConfig))
+		}
+		if requestSSL {
+			sslTimeout, _ := cmd.Flags().GetDuration("ssl-timeout")
+			if createdHost, err = enableHostSSL(client, *createdHost, sslSettings, email, sslTimeout); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(out, "SSL: %s\n", sslStatus(*createdHost))
+
+		if waitForOnline {
+			waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+			if _, err := client.WaitForOnline(createdHost.ID, waitTimeout); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "Proxy host is online")
+		}
 
 		return nil
 	},
 }
 
+// replaceProxyHost deletes the existing host serving any of the domains, if
+// any, so that create can recreate it from scratch. Settings of the old host
+// are not kept. The old host is always backed up first, to backupDir or else
+// to defaultBackupDir, and returned together with the path of the backup.
+func replaceProxyHost(client *APIClient, domains []string, yes bool, backupDir string) (*ProxyHost, string, error) {
+	hosts, err := client.ListProxyHosts()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list proxy hosts: %w", err)
+	}
+
+	var matches []ProxyHost
+	seen := make(map[int]bool)
+	for _, domain := range domains {
+		for _, host := range findProxyHostsByDomain(hosts, domain) {
+			if !seen[host.ID] {
+				seen[host.ID] = true
+				matches = append(matches, host)
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil, "", nil
+	}
+	if len(matches) > 1 {
+		return nil, "", fmt.Errorf("the domains are served by %d proxy hosts, refusing to replace", len(matches))
+	}
+
+	existing := matches[0]
+	if !yes && !confirm(fmt.Sprintf("Replace proxy host %d %v? All of its settings not given as flags will be lost", existing.ID, existing.DomainNames)) {
+		return nil, "", fmt.Errorf("aborted")
+	}
+
+	if backupDir == "" {
+		if backupDir, err = defaultBackupDir(); err != nil {
+			return nil, "", err
+		}
+	}
+	backupPath, err := backupProxyHosts(backupDir, []ProxyHost{existing})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := client.DeleteProxyHost(existing.ID); err != nil {
+		return nil, backupPath, fmt.Errorf("failed to delete existing proxy host: %w (backup at %s)", err, backupPath)
+	}
+
+	fmt.Fprintf(out, "Deleted existing proxy host with ID: %d\n", existing.ID)
+	return &existing, backupPath, nil
+}
+
+// restoreReplacedProxyHost recreates the host deleted by replaceProxyHost
+// after its replacement could not be created, and returns createErr
+func restoreReplacedProxyHost(client *APIClient, old ProxyHost, backupPath string, createErr error) error {
+	old.ID = 0
+	old.CreatedOn = ""
+	old.ModifiedOn = ""
+
+	restored, err := client.CreateProxyHost(old)
+	if err != nil {
+		return fmt.Errorf("failed to create proxy host: %w; restoring the replaced host failed too: %v (backup at %s)", createErr, err, backupPath)
+	}
+	fmt.Fprintf(os.Stderr, "Restored the replaced proxy host with ID: %d\n", restored.ID)
+	return fmt.Errorf("failed to create proxy host: %w (the replaced host was restored with ID %d, backup at %s)", createErr, restored.ID, backupPath)
+}
+
 var deleteCmd = &cobra.Command{
-	Use:   "delete",
-	Short: "Delete a proxy host by ID",
+	Use:   "delete [ID...]",
+	Short: "Delete proxy hosts by ID, domain or filter",
+	Long: `Delete proxy hosts given by ID, by one of their domains with --domain, or by
+all hosts matching --filter expressions. Several hosts are deleted concurrently
+and a summary is printed at the end.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate required parameters before authentication
 		id, _ := cmd.Flags().GetInt("id")
-		if id == 0 {
-			return fmt.Errorf("id is required")
+		domain, _ := cmd.Flags().GetString("domain")
+		matchWildcards, _ := cmd.Flags().GetBool("match-wildcards")
+		allMatches, _ := cmd.Flags().GetBool("all-matches")
+		filterExprs, _ := cmd.Flags().GetStringArray("filter")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		timeoutPerItem, _ := cmd.Flags().GetDuration("timeout-per-item")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 && (id != 0 || domain != "") {
+			return fmt.Errorf("IDs as arguments cannot be combined with --id or --domain")
+		}
+		if len(filterExprs) > 0 && (len(ids) > 0 || id != 0 || domain != "") {
+			return fmt.Errorf("--filter cannot be combined with IDs or --domain")
+		}
+		if len(ids) == 0 && id == 0 && domain == "" && len(filterExprs) == 0 {
+			return fmt.Errorf("id, domain or filter is required")
+		}
+		if allMatches && (domain == "" || id != 0) {
+			return fmt.Errorf("--all-matches requires --domain and cannot be combined with --id")
+		}
+
+		filters, err := parseFieldFilters(filterExprs)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
 		}
 
-		client := NewAPIClient(apiURL)
-		
-		if err := client.Authenticate(username, password); err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+		if id != 0 && domain == "" {
+			ids = []int{id}
 		}
+		if domain != "" || len(filters) > 0 {
+			hosts, err := client.ListProxyHosts()
+			if err != nil {
+				return fmt.Errorf("failed to list proxy hosts: %w", err)
+			}
+
+			var matches []ProxyHost
+			switch {
+			case len(filters) > 0:
+				if matches = filterProxyHosts(hosts, filters); len(matches) == 0 {
+					fmt.Fprintln(out, "No proxy hosts match the filters")
+					return nil
+				}
+			case allMatches:
+				if matches = lookupProxyHostsByDomain(hosts, domain, matchWildcards); len(matches) == 0 {
+					return fmt.Errorf("no proxy host found for domain %s", domain)
+				}
+			default:
+				host, err := selectProxyHostByDomain(hosts, domain, matchWildcards, id)
+				if err != nil {
+					return err
+				}
+				matches = []ProxyHost{*host}
+			}
+
+			for _, host := range matches {
+				ids = append(ids, host.ID)
+			}
 
-		if err := client.DeleteProxyHost(id); err != nil {
-			return fmt.Errorf("failed to delete proxy host: %w", err)
+			// Filters can match far more than intended, so show what goes
+			if len(filters) > 0 && !yes {
+				for _, host := range matches {
+					fmt.Fprintf(os.Stderr, "  %d %v\n", host.ID, host.DomainNames)
+				}
+				if !confirm(fmt.Sprintf("Delete these %d proxy hosts?", len(matches))) {
+					return fmt.Errorf("aborted")
+				}
+			}
 		}
 
-		fmt.Printf("Successfully deleted proxy host with ID: %d\n", id)
+		var backupPath string
+		if backupDir, _ := cmd.Flags().GetString("backup-before"); backupDir != "" {
+			hosts := make([]ProxyHost, 0, len(ids))
+			for _, id := range ids {
+				host, err := client.GetProxyHost(id)
+				if err != nil {
+					return fmt.Errorf("failed to get proxy host for backup: %w", err)
+				}
+				hosts = append(hosts, *host)
+			}
+			if backupPath, err = backupProxyHosts(backupDir, hosts); err != nil {
+				return err
+			}
+		}
+
+		errs := runConcurrently(len(ids), parallel, func(i int) error {
+			return client.runWithTimeout(timeoutPerItem, func(client *APIClient) error {
+				return client.DeleteProxyHost(ids[i])
+			})
+		})
+
+		if len(ids) == 1 {
+			if err := errs[0]; err != nil {
+				if backupPath != "" {
+					return fmt.Errorf("failed to delete proxy host: %w (backup at %s)", err, backupPath)
+				}
+				return fmt.Errorf("failed to delete proxy host: %w", err)
+			}
+			fmt.Fprintf(out, "Successfully deleted proxy host with ID: %d\n", ids[0])
+			return nil
+		}
+
+		var result BatchResult
+		for i, err := range errs {
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to delete proxy host %d: %v\n", ids[i], err)
+				continue
+			}
+			fmt.Fprintf(out, "Successfully deleted proxy host with ID: %d\n", ids[i])
+		}
+
+		fmt.Fprintf(out, "Delete finished: %s\n", result)
+		if err := result.Err(); err != nil {
+			if backupPath != "" {
+				return fmt.Errorf("%w (backup at %s)", err, backupPath)
+			}
+			return err
+		}
 		return nil
 	},
 }
@@ -278,15 +1441,78 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&apiURL, "api-url", "a", "http://dockernuc:81/api", "Nginx Proxy Manager API URL")
 	rootCmd.PersistentFlags().StringVarP(&username, "username", "u", "", "Username for authentication")
 	rootCmd.PersistentFlags().StringVarP(&password, "password", "p", "", "Password for authentication")
+	rootCmd.PersistentFlags().StringVarP(&profileName, "profile", "P", "", "Use the connection settings of a profile from the config file")
+	rootCmd.PersistentFlags().StringVar(&credentialStore, "credential-store", "file", "Where login stores credentials (file or keyring)")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Path of the config file (default: nginxproxymanager-cli/config.json in the user config directory)")
+	rootCmd.PersistentFlags().StringVarP(&token, "token", "t", "", "Pre-issued API token, skips username/password authentication")
+	rootCmd.PersistentFlags().StringVar(&identityField, "identity-field", "identity", "JSON field used to send the username when authenticating")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "Output format (text, json or none)")
+	rootCmd.PersistentFlags().StringVar(&outFile, "out-file", "", "Write the command output to a file instead of stdout")
+	rootCmd.PersistentFlags().BoolVar(&preferIPv4, "prefer-ipv4", false, "Only use IPv4 to connect to the API")
+	rootCmd.PersistentFlags().BoolVar(&preferIPv6, "prefer-ipv6", false, "Only use IPv6 to connect to the API")
+	rootCmd.PersistentFlags().StringVar(&tlsMinVersionName, "tls-min-version", "1.2", "Minimum TLS version for HTTPS connections to the API (1.2 or 1.3)")
+	rootCmd.PersistentFlags().StringArrayVar(&aliasValues, "field-alias", nil, "Read a proxy host field from another name as npm_field=fork_field (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&showCurl, "show-curl", false, "Print the equivalent curl command of every API request to stderr")
+	rootCmd.PersistentFlags().BoolVar(&showSecrets, "show-secrets", false, "Don't redact tokens and passwords in --show-curl output")
+	rootCmd.PersistentFlags().StringVar(&recordFile, "record", "", "Save all API requests and responses to a file, with secrets scrubbed")
+	rootCmd.PersistentFlags().StringVar(&replayFile, "replay", "", "Answer API requests from a file written by --record instead of the network")
+	rootCmd.PersistentFlags().Int64Var(&maxResponseSize, "max-response-size", defaultMaxResponseSize, "Maximum size in bytes of an API response")
+
+	// List command flags
+	listCmd.Flags().Duration("watch", 0, "Refresh the list at the given interval (e.g. 10s)")
+	listCmd.Flags().Bool("include-disabled", true, "Include disabled proxy hosts")
+	listCmd.Flags().Bool("disabled-only", false, "Only show disabled proxy hosts (alias --disabled)")
+	listCmd.Flags().Bool("enabled", false, "Only show enabled proxy hosts")
+	listCmd.Flags().String("domain", "", "Only show proxy hosts with a domain matching this glob, like *.example.com")
+	listCmd.Flags().String("forward-host", "", "Only show proxy hosts forwarding to a host matching this glob")
+	listCmd.Flags().Int("cert-id", 0, "Only show proxy hosts using this certificate")
+	listCmd.Flags().Bool("no-cert", false, "Only show proxy hosts without a certificate")
+	listCmd.Flags().Bool("count", false, "Only print the number of matching proxy hosts")
+	listCmd.Flags().Bool("summary", false, "Print aggregate counts after the proxy hosts")
+	listCmd.Flags().Bool("all-profiles", false, "List the proxy hosts of all configured profiles")
+	listCmd.Flags().String("sort-by", "id", "Order of the proxy hosts: id, domain or modified_on")
+	listCmd.Flags().String("columns", "", "Print a table of these comma separated columns, e.g. id,domains,forward,ssl")
+	listCmd.MarkFlagsMutuallyExclusive("enabled", "disabled-only")
+	// --disabled is another name of --disabled-only, not a flag of its own
+	listCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "disabled" {
+			name = "disabled-only"
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	// Create command flags
-	createCmd.Flags().String("domain", "", "Domain name for the proxy host")
+	createCmd.Flags().StringP("file", "f", "", "Read the proxy host from a YAML or JSON file, - for stdin")
+	createCmd.Flags().StringSlice("domain", nil, "Domain name for the proxy host (repeatable or comma separated)")
 	createCmd.Flags().String("forward-host", "", "Forward host")
 	createCmd.Flags().Int("forward-port", 0, "Forward port")
 	createCmd.Flags().String("forward-scheme", "http", "Forward scheme (http or https)")
+	addSSLFlags(createCmd)
+	addCreateSSLFlags(createCmd)
+	addHostOptionFlags(createCmd)
+	addAccessListFlags(createCmd)
+	addLocationFlags(createCmd)
+	addAdvancedConfigFlag(createCmd)
+	addHostHeaderFlags(createCmd)
+	addExtraJSONFlag(createCmd)
+	createCmd.Flags().Bool("force", false, "Create the proxy host even if safety checks fail")
+	createCmd.Flags().Bool("disabled", false, "Create the proxy host disabled, it serves no traffic until enabled")
+	createCmd.Flags().Bool("wait-for-online", false, "Wait until nginx reports the proxy host online")
+	createCmd.Flags().Duration("wait-timeout", 60*time.Second, "Maximum time to wait with --wait-for-online")
+	createCmd.Flags().Bool("replace", false, "Delete an existing host with the same domain and create it fresh")
+	createCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	createCmd.Flags().String("backup-before", "", "Back up a replaced host to a timestamped file in this directory (default: the backups directory in the user cache directory)")
 
 	// Delete command flags
 	deleteCmd.Flags().Int("id", 0, "ID of the proxy host to delete")
+	deleteCmd.Flags().String("domain", "", "Domain of the proxy host to delete")
+	deleteCmd.Flags().Bool("match-wildcards", false, "Also match hosts with a wildcard domain covering --domain")
+	deleteCmd.Flags().Bool("all-matches", false, "Delete every proxy host serving --domain instead of refusing when several match")
+	deleteCmd.Flags().StringArray("filter", nil, "Delete all proxy hosts matching field=value or field!=value (repeatable, all must match)")
+	deleteCmd.Flags().Int("parallel", 5, "Maximum number of concurrent deletions")
+	deleteCmd.Flags().Duration("timeout-per-item", 0, "Maximum time for deleting a single proxy host, 0 for no limit")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation before deleting by filter")
+	deleteCmd.Flags().String("backup-before", "", "Back up the host to a timestamped file in this directory before deleting")
 
 	// Add commands
 	rootCmd.AddCommand(listCmd)
@@ -314,8 +1540,34 @@ func main() {
 		}
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	if token == "" {
+		if envToken := os.Getenv("NPM_TOKEN"); envToken != "" {
+			token = envToken
+		}
+	}
+
+	if profileName == "" {
+		if envProfile := os.Getenv("NPM_PROFILE"); envProfile != "" {
+			profileName = envProfile
+		}
+	}
+
+	if envStore := os.Getenv("NPM_CREDENTIAL_STORE"); envStore != "" {
+		credentialStore = envStore
+	}
+
+	err := rootCmd.Execute()
+	// A failed command skips PersistentPostRunE, so the output file may still be open
+	if closeErr := closeOutFile(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
\ No newline at end of file
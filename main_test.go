@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestBulkDeleteRenewsTokenOnce checks that concurrent requests rejected for
+// the same expired token make the client authenticate only once
+func TestBulkDeleteRenewsTokenOnce(t *testing.T) {
+	var tokenCalls, deletes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/tokens":
+			tokenCalls.Add(1)
+			w.Write([]byte(`{"token":"fresh","expires":"2099-01-01T00:00:00Z"}`))
+		case r.Header.Get("Authorization") != "Bearer fresh":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/api/nginx/proxy-hosts/"):
+			deletes.Add(1)
+			w.Write([]byte(`true`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL + "/api")
+	client.Token = "expired"
+	client.username, client.password = "admin@example.com", "secret"
+
+	ids := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	errs := runConcurrently(len(ids), 4, func(i int) error {
+		return client.DeleteProxyHost(ids[i])
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("delete of proxy host %d failed: %v", ids[i], err)
+		}
+	}
+	if got := tokenCalls.Load(); got != 1 {
+		t.Errorf("got %d calls to /tokens, want exactly 1", got)
+	}
+	if got := deletes.Load(); got != int32(len(ids)) {
+		t.Errorf("got %d deletes, want %d", got, len(ids))
+	}
+}
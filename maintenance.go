@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maintenanceStateFile is the state file holding the original settings of hosts in maintenance
+const maintenanceStateFile = "maintenance.json"
+
+// maintenanceMarker starts the advanced config that serves the maintenance page
+const maintenanceMarker = "# nginxproxymanager-cli maintenance mode"
+
+// maintenanceState is what maintenance on saved of a host, to be restored by maintenance off
+type maintenanceState struct {
+	Time           time.Time     `json:"time"`
+	APIURL         string        `json:"api_url"`
+	HostID         int           `json:"host_id"`
+	Target         forwardTarget `json:"target"`
+	AdvancedConfig string        `json:"advanced_config"`
+	// Maintenance is the target the host was switched to, empty when a page is served instead
+	Maintenance *forwardTarget `json:"maintenance,omitempty"`
+}
+
+// readMaintenanceStates reads the saved state of all hosts in maintenance
+func readMaintenanceStates() ([]maintenanceState, error) {
+	var states []maintenanceState
+	err := readStateFile(maintenanceStateFile, &states)
+	return states, err
+}
+
+// findMaintenanceState returns the index of the saved state of a host, or -1
+func findMaintenanceState(states []maintenanceState, apiURL string, hostID int) int {
+	for i, state := range states {
+		if state.APIURL == apiURL && state.HostID == hostID {
+			return i
+		}
+	}
+	return -1
+}
+
+// maintenanceConfig returns an advanced config answering every request with
+// the page and status 503. The return in server context runs before any location.
+// nginx expands variables even in single quotes and has no escape for $, so
+// it becomes the HTML entity &#36;, which browsers show as $ outside of
+// scripts and styles.
+func maintenanceConfig(page string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `$`, `&#36;`).Replace(page)
+	return fmt.Sprintf("%s\ndefault_type text/html;\nadd_header Retry-After 300 always;\nreturn 503 '%s';\n", maintenanceMarker, escaped)
+}
+
+// defaultMaintenancePage renders a minimal maintenance page with the message
+func defaultMaintenancePage(message string) string {
+	return fmt.Sprintf("<!DOCTYPE html><html><head><title>Maintenance</title></head><body><h1>%s</h1></body></html>", html.EscapeString(message))
+}
+
+// inMaintenance reports whether the host still has the maintenance settings applied
+func inMaintenance(host ProxyHost, state maintenanceState) bool {
+	if state.Maintenance != nil {
+		return hostTarget(host) == *state.Maintenance
+	}
+	return strings.HasPrefix(host.AdvancedConfig, maintenanceMarker)
+}
+
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "Put proxy hosts into maintenance mode and back",
+}
+
+var maintenanceOnCmd = &cobra.Command{
+	Use:   "on ID",
+	Short: "Serve a maintenance page or upstream for a proxy host",
+	Long: `Serve a maintenance page for a proxy host, or forward it to a maintenance
+upstream with --forward-host and --forward-port.
+
+The original advanced config and forward target are saved locally next to the
+config file, so maintenance off can restore them exactly.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		id := ids[0]
+
+		forwardHost, _ := cmd.Flags().GetString("forward-host")
+		forwardPort, _ := cmd.Flags().GetInt("forward-port")
+		message, _ := cmd.Flags().GetString("message")
+		pageFile, _ := cmd.Flags().GetString("page")
+		upstream := forwardHost != "" || forwardPort != 0
+		if upstream && (forwardHost == "" || forwardPort == 0) {
+			return fmt.Errorf("forward-host and forward-port must be given together")
+		}
+		if upstream && (pageFile != "" || cmd.Flags().Changed("message")) {
+			return fmt.Errorf("--page and --message cannot be combined with a maintenance upstream")
+		}
+
+		page := defaultMaintenancePage(message)
+		if pageFile != "" {
+			content, err := os.ReadFile(pageFile)
+			if err != nil {
+				return fmt.Errorf("failed to read maintenance page: %w", err)
+			}
+			page = string(content)
+		}
+
+		states, err := readMaintenanceStates()
+		if err != nil {
+			return err
+		}
+		if findMaintenanceState(states, apiURL, id) >= 0 {
+			return fmt.Errorf("proxy host %d is already in maintenance mode, turn it off first", id)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(id)
+		if err != nil {
+			return err
+		}
+
+		state := maintenanceState{
+			Time:           time.Now().UTC(),
+			APIURL:         apiURL,
+			HostID:         host.ID,
+			Target:         hostTarget(*host),
+			AdvancedConfig: host.AdvancedConfig,
+		}
+
+		updated := *host
+		if upstream {
+			target := forwardTarget{Scheme: host.ForwardScheme, Host: forwardHost, Port: forwardPort}
+			if cmd.Flags().Changed("forward-scheme") {
+				target.Scheme, _ = cmd.Flags().GetString("forward-scheme")
+			}
+			state.Maintenance = &target
+			updated.ForwardScheme, updated.ForwardHost, updated.ForwardPort = target.Scheme, target.Host, target.Port
+		} else {
+			updated.AdvancedConfig = maintenanceConfig(page)
+		}
+
+		// Save the original settings first, so a failure below never loses them
+		if err := writeStateFile(maintenanceStateFile, append(states, state)); err != nil {
+			return err
+		}
+
+		if _, err := client.UpdateProxyHost(host.ID, updated); err != nil {
+			if err := writeStateFile(maintenanceStateFile, states); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Proxy host %d %v is in maintenance mode\n", host.ID, host.DomainNames)
+		if upstream {
+			fmt.Fprintf(out, "Forwarding to %s instead of %s\n", state.Maintenance, state.Target)
+		}
+		return nil
+	},
+}
+
+var maintenanceOffCmd = &cobra.Command{
+	Use:   "off ID",
+	Short: "Restore a proxy host from maintenance mode",
+	Long: `Restore the advanced config and forward target a proxy host had before
+maintenance on.
+
+If the host was changed while in maintenance, the restore is refused unless
+--force is given, as it would undo those changes.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		id := ids[0]
+		force, _ := cmd.Flags().GetBool("force")
+
+		states, err := readMaintenanceStates()
+		if err != nil {
+			return err
+		}
+		i := findMaintenanceState(states, apiURL, id)
+		if i < 0 {
+			return fmt.Errorf("proxy host %d is not in maintenance mode, no saved state found", id)
+		}
+		state := states[i]
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(id)
+		if err != nil {
+			return err
+		}
+		if !inMaintenance(*host, state) && !force {
+			return fmt.Errorf("proxy host %d was changed since maintenance on; use --force to restore the saved settings anyway", id)
+		}
+
+		host.ForwardScheme = state.Target.Scheme
+		host.ForwardHost = state.Target.Host
+		host.ForwardPort = state.Target.Port
+		host.AdvancedConfig = state.AdvancedConfig
+
+		if _, err := client.UpdateProxyHost(id, *host); err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+		if err := writeStateFile(maintenanceStateFile, append(states[:i], states[i+1:]...)); err != nil {
+			return fmt.Errorf("proxy host %d was restored, but %w", id, err)
+		}
+
+		fmt.Fprintf(out, "Proxy host %d %v is out of maintenance mode\n", host.ID, host.DomainNames)
+		fmt.Fprintf(out, "Forwarding to %s\n", state.Target)
+		return nil
+	},
+}
+
+func init() {
+	maintenanceOnCmd.Flags().String("message", "This site is down for maintenance.", "Message shown on the maintenance page")
+	maintenanceOnCmd.Flags().String("page", "", "HTML file to serve as maintenance page")
+	maintenanceOnCmd.Flags().String("forward-host", "", "Forward to this maintenance host instead of serving a page")
+	maintenanceOnCmd.Flags().Int("forward-port", 0, "Port of the maintenance host")
+	maintenanceOnCmd.Flags().String("forward-scheme", "", "Scheme of the maintenance host (default: keep the current one)")
+
+	maintenanceOffCmd.Flags().Bool("force", false, "Restore the saved settings even if the host was changed in maintenance mode")
+
+	maintenanceCmd.AddCommand(maintenanceOnCmd)
+	maintenanceCmd.AddCommand(maintenanceOffCmd)
+	rootCmd.AddCommand(maintenanceCmd)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// migrateAccessList returns the ID of an access list on the target equivalent
+// to the source access list, creating it when no list with the same name exists
+func migrateAccessList(source, target *APIClient, id int) (int, error) {
+	list, err := source.GetAccessList(id)
+	if err != nil {
+		return 0, err
+	}
+
+	existing, err := target.ListAccessLists()
+	if err != nil {
+		return 0, err
+	}
+	for _, candidate := range existing {
+		if candidate.Name == list.Name {
+			fmt.Fprintf(out, "Using existing access list %d %q on target\n", candidate.ID, candidate.Name)
+			return candidate.ID, nil
+		}
+	}
+
+	// NPM never returns the passwords of basic auth users, so they can't be copied
+	if len(list.Items) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: access list %q has %d basic auth users, their passwords must be set again on the target\n", list.Name, len(list.Items))
+	}
+
+	created, err := target.CreateAccessList(AccessList{
+		Name:       list.Name,
+		SatisfyAny: list.SatisfyAny,
+		PassAuth:   list.PassAuth,
+		Items:      list.Items,
+		Clients:    list.Clients,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(out, "Created access list %d %q on target (was %d)\n", created.ID, created.Name, id)
+	return created.ID, nil
+}
+
+// migrateCertificate returns the ID of a certificate on the target covering
+// all domains of the host, requesting a new Let's Encrypt certificate when needed
+func migrateCertificate(source, target *APIClient, id int, domains []string) (int, error) {
+	existing, err := target.ListCertificates()
+	if err != nil {
+		return 0, err
+	}
+	for _, candidate := range existing {
+		if len(uncoveredDomains(domains, candidate.DomainNames)) == 0 {
+			fmt.Fprintf(out, "Using existing certificate %d %v on target\n", candidate.ID, candidate.DomainNames)
+			return candidate.ID, nil
+		}
+	}
+
+	cert, err := source.GetCertificate(id)
+	if err != nil {
+		return 0, err
+	}
+
+	// Custom certificates can't be downloaded with their private key
+	if cert.Provider != "letsencrypt" {
+		return 0, fmt.Errorf("certificate %d is a %s certificate, upload it to the target first", id, cert.Provider)
+	}
+
+	fmt.Fprintf(out, "Requesting Let's Encrypt certificate for %v on target...\n", cert.DomainNames)
+	created, err := target.CreateCertificate(Certificate{
+		Provider:    cert.Provider,
+		NiceName:    cert.NiceName,
+		DomainNames: cert.DomainNames,
+		Meta:        cert.Meta,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(out, "Created certificate %d on target (was %d)\n", created.ID, id)
+	return created.ID, nil
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move a proxy host to another instance and delete it from the source",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		sourceProfile, _ := cmd.Flags().GetString("source-profile")
+		targetProfile, _ := cmd.Flags().GetString("target-profile")
+		id, _ := cmd.Flags().GetInt("id")
+		yes, _ := cmd.Flags().GetBool("yes")
+		waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+		backupDir, _ := cmd.Flags().GetString("backup-before")
+		if sourceProfile == "" || targetProfile == "" || id == 0 {
+			return fmt.Errorf("source-profile, target-profile and id are required")
+		}
+		if sourceProfile == targetProfile {
+			return fmt.Errorf("source and target profile must differ")
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		source, err := newProfileClient(config, sourceProfile)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", sourceProfile, err)
+		}
+		target, err := newProfileClient(config, targetProfile)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", targetProfile, err)
+		}
+
+		host, err := source.GetProxyHost(id)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy host: %w", err)
+		}
+
+		targetHosts, err := target.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts on target: %w", err)
+		}
+		for _, domain := range host.DomainNames {
+			if matches := findProxyHostsByDomain(targetHosts, domain); len(matches) > 0 {
+				return fmt.Errorf("domain %s is already served by proxy host %d on target", domain, matches[0].ID)
+			}
+		}
+
+		newHost := *host
+		newHost.ID = 0
+		newHost.CreatedOn = ""
+		newHost.ModifiedOn = ""
+		newHost.Meta = ProxyHostMeta{}
+
+		if host.AccessListID != 0 {
+			if newHost.AccessListID, err = migrateAccessList(source, target, host.AccessListID); err != nil {
+				return fmt.Errorf("failed to migrate access list: %w", err)
+			}
+		}
+		if host.CertificateID != 0 {
+			if newHost.CertificateID, err = migrateCertificate(source, target, host.CertificateID, host.DomainNames); err != nil {
+				return fmt.Errorf("failed to migrate certificate: %w", err)
+			}
+		}
+
+		created, err := target.CreateProxyHost(newHost)
+		if err != nil {
+			return fmt.Errorf("failed to create proxy host on target: %w", err)
+		}
+		fmt.Fprintf(out, "Created proxy host %d on %s (was %d on %s)\n", created.ID, targetProfile, id, sourceProfile)
+
+		// Disabled hosts never come online, there is nothing to verify for them
+		if created.Enabled {
+			fmt.Fprintf(out, "Waiting up to %s for proxy host %d to come online...\n", waitTimeout, created.ID)
+			if _, err := target.WaitForOnline(created.ID, waitTimeout); err != nil {
+				return fmt.Errorf("verification failed, source proxy host %d was kept: %w", id, err)
+			}
+			fmt.Fprintf(out, "Proxy host %d is online on %s\n", created.ID, targetProfile)
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Delete proxy host %d %v from %s?", id, host.DomainNames, sourceProfile)) {
+			return fmt.Errorf("aborted, source proxy host %d was kept", id)
+		}
+
+		var backupPath string
+		if backupDir != "" {
+			if backupPath, err = backupProxyHosts(backupDir, []ProxyHost{*host}); err != nil {
+				return err
+			}
+		}
+
+		if err := source.DeleteProxyHost(id); err != nil {
+			if backupPath != "" {
+				return fmt.Errorf("failed to delete proxy host from source: %w (backed up to %s)", err, backupPath)
+			}
+			return fmt.Errorf("failed to delete proxy host from source: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully migrated proxy host\n")
+		fmt.Fprintf(out, "Old ID: %d (%s)\n", id, sourceProfile)
+		fmt.Fprintf(out, "New ID: %d (%s)\n", created.ID, targetProfile)
+
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().String("source-profile", "", "Profile of the instance to move the proxy host from")
+	migrateCmd.Flags().String("target-profile", "", "Profile of the instance to move the proxy host to")
+	migrateCmd.Flags().Int("id", 0, "ID of the proxy host on the source")
+	migrateCmd.Flags().BoolP("yes", "y", false, "Delete the source proxy host without asking for confirmation")
+	migrateCmd.Flags().Duration("wait-timeout", 60*time.Second, "Maximum time to wait for the new proxy host to come online")
+	migrateCmd.Flags().String("backup-before", "", "Directory to write a backup of the source proxy host to before deleting it")
+
+	rootCmd.AddCommand(migrateCmd)
+}
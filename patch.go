@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// mergePatch applies a JSON merge patch (RFC 7386) to a decoded JSON value.
+// Objects are merged recursively, null removes a member and any other value
+// replaces the target.
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// applyProxyHostPatch merges a JSON merge patch into the editable fields of a
+// host. Fields removed by the patch are reset to their zero value.
+func applyProxyHostPatch(host ProxyHost, patch []byte) (ProxyHost, error) {
+	var patchValue any
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return host, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	patchObject, ok := patchValue.(map[string]any)
+	if !ok {
+		return host, fmt.Errorf("invalid merge patch, expected a JSON object")
+	}
+	fields := make(map[string]json.RawMessage, len(patchObject))
+	for field := range patchObject {
+		fields[field] = nil
+	}
+	if err := checkEditableFields(fields); err != nil {
+		return host, err
+	}
+
+	current, err := editableFields(host)
+	if err != nil {
+		return host, err
+	}
+	jsonData, err := json.Marshal(current)
+	if err != nil {
+		return host, fmt.Errorf("failed to marshal proxy host: %w", err)
+	}
+
+	var target any
+	if err := json.Unmarshal(jsonData, &target); err != nil {
+		return host, fmt.Errorf("failed to decode proxy host: %w", err)
+	}
+
+	if jsonData, err = json.Marshal(mergePatch(target, patchValue)); err != nil {
+		return host, fmt.Errorf("failed to marshal patched proxy host: %w", err)
+	}
+
+	var patched ProxyHost
+	if err := json.Unmarshal(jsonData, &patched); err != nil {
+		return host, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	patched.ID = host.ID
+	patched.CreatedOn = host.CreatedOn
+	patched.ModifiedOn = host.ModifiedOn
+	patched.Meta = host.Meta
+	return patched, nil
+}
+
+// changedFields returns the editable fields that differ between two hosts, sorted by name
+func changedFields(before, after map[string]json.RawMessage) []string {
+	var changed []string
+	for field, value := range after {
+		if !jsonEqual(before[field], value) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// jsonEqual reports whether two JSON documents are equal, ignoring formatting
+func jsonEqual(a, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
+
+var patchCmd = &cobra.Command{
+	Use:   "patch ID",
+	Short: "Apply a JSON merge patch to a proxy host",
+	Long: `Apply a JSON merge patch (RFC 7386) to a proxy host, for example:
+
+  echo '{"caching_enabled": true}' | patch 4
+
+The patch is read from stdin, or from the file given with --file. Fields in the
+patch replace those of the host, objects are merged recursively and null
+resets a field. Lists like domain_names and locations are replaced as a whole.
+The result is validated like in the edit command before it is sent.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		file, _ := cmd.Flags().GetString("file")
+		var patch []byte
+		if file == "-" {
+			patch, err = io.ReadAll(os.Stdin)
+		} else {
+			patch, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read merge patch: %w", err)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		patched, err := applyProxyHostPatch(*host, patch)
+		if err != nil {
+			return err
+		}
+		if err := validateProxyHost(patched); err != nil {
+			return err
+		}
+
+		before, err := editableFields(*host)
+		if err != nil {
+			return err
+		}
+		after, err := editableFields(patched)
+		if err != nil {
+			return err
+		}
+		changed := changedFields(before, after)
+		if len(changed) == 0 {
+			fmt.Fprintf(out, "Proxy host %d is unchanged\n", host.ID)
+			return nil
+		}
+
+		updatedHost, err := client.UpdateProxyHost(host.ID, patched)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully updated proxy host with ID: %d\n", updatedHost.ID)
+		for _, field := range changed {
+			fmt.Fprintf(out, "%s: %s -> %s\n", field, before[field], after[field])
+		}
+		return nil
+	},
+}
+
+func init() {
+	patchCmd.Flags().StringP("file", "f", "-", "File with the merge patch, - for stdin")
+
+	rootCmd.AddCommand(patchCmd)
+}
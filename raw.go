@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// parseHeaders parses "Key: Value" strings into an http.Header
+func parseHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Key: Value\"", value)
+		}
+		header.Add(key, strings.TrimSpace(val))
+	}
+	return header, nil
+}
+
+// readRequestBody resolves the --data and --data-file flags into a request body.
+// Like curl, a --data value starting with @ is read from the named file.
+func readRequestBody(data, dataFile string) (io.Reader, error) {
+	if data != "" && dataFile != "" {
+		return nil, fmt.Errorf("--data and --data-file cannot be used together")
+	}
+	if strings.HasPrefix(data, "@") {
+		dataFile = strings.TrimPrefix(data, "@")
+		data = ""
+	}
+
+	if dataFile != "" {
+		content, err := os.ReadFile(dataFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data file: %w", err)
+		}
+		return bytes.NewReader(content), nil
+	}
+	if data != "" {
+		return strings.NewReader(data), nil
+	}
+
+	return nil, nil
+}
+
+var rawCmd = &cobra.Command{
+	Use:   "raw METHOD PATH",
+	Short: "Run an arbitrary authenticated API request",
+	Long: `Run an arbitrary authenticated request against the Nginx Proxy Manager API.
+
+PATH is relative to the API URL, for example /nginx/proxy-hosts/5.
+The response status is written to stderr and the response body to stdout.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		method := strings.ToUpper(args[0])
+		endpoint := args[1]
+		if !strings.HasPrefix(endpoint, "/") {
+			endpoint = "/" + endpoint
+		}
+
+		data, _ := cmd.Flags().GetString("data")
+		dataFile, _ := cmd.Flags().GetString("data-file")
+		headerValues, _ := cmd.Flags().GetStringArray("header")
+
+		body, err := readRequestBody(data, dataFile)
+		if err != nil {
+			return err
+		}
+
+		header, err := parseHeaders(headerValues)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		// Keep 401 and 403 responses, their body tells why the request failed
+		resp, err := client.sendAuthenticatedRequest(method, endpoint, body, header)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		fmt.Fprintf(os.Stderr, "Status: %s\n", resp.Status)
+		if _, err := io.Copy(out, resp.Body); err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		fmt.Fprintln(out)
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("request failed with status: %d", resp.StatusCode)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rawCmd.Flags().StringP("data", "d", "", "Request body, or @file to read it from a file")
+	rawCmd.Flags().String("data-file", "", "Read the request body from a file")
+	rawCmd.Flags().StringArrayP("header", "H", nil, "Additional request header as \"Key: Value\" (repeatable)")
+
+	rootCmd.AddCommand(rawCmd)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	recordFile string
+	replayFile string
+
+	// sessionTransport replaces the network transport of all API clients
+	// while recording or replaying, see --record and --replay
+	sessionTransport http.RoundTripper
+)
+
+// Recording is a file of API request and response pairs
+type Recording struct {
+	Exchanges []RecordedExchange `json:"exchanges"`
+}
+
+// RecordedExchange is a single API request with its response. Secrets are
+// scrubbed from both bodies and request headers are not recorded at all.
+// Bodies that aren't valid UTF-8, like certificate downloads, are stored
+// base64 encoded with the encoding field set to "base64".
+type RecordedExchange struct {
+	Method               string      `json:"method"`
+	Path                 string      `json:"path"`
+	RequestBody          string      `json:"request_body,omitempty"`
+	RequestBodyEncoding  string      `json:"request_body_encoding,omitempty"`
+	Status               int         `json:"status"`
+	ResponseHeader       http.Header `json:"response_header,omitempty"`
+	ResponseBody         string      `json:"response_body"`
+	ResponseBodyEncoding string      `json:"response_body_encoding,omitempty"`
+}
+
+// encodeBody returns a body for a recording and its encoding. Text is kept
+// readable, anything else is base64 encoded so it survives the JSON file.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// decodeBody reverses encodeBody
+func decodeBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("unknown body encoding %q", encoding)
+	}
+}
+
+// recordingTransport passes requests on and saves every exchange to a file
+type recordingTransport struct {
+	next http.RoundTripper
+	path string
+
+	mu        sync.Mutex
+	recording Recording
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		if requestBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	exchange := RecordedExchange{
+		Method:         req.Method,
+		Path:           req.URL.RequestURI(),
+		Status:         resp.StatusCode,
+		ResponseHeader: scrubHeader(resp.Header),
+	}
+	exchange.ResponseBody, exchange.ResponseBodyEncoding = encodeBody(redactBody(responseBody))
+	if len(requestBody) > 0 {
+		exchange.RequestBody, exchange.RequestBodyEncoding = encodeBody(redactBody(requestBody))
+	}
+
+	// Save after every exchange so a failing command still leaves a recording
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.recording.Exchanges = append(t.recording.Exchanges, exchange)
+	if err := writeRecording(t.path, t.recording); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// scrubHeader drops response headers that may carry credentials
+func scrubHeader(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	scrubbed.Del("Set-Cookie")
+	scrubbed.Del("Authorization")
+	return scrubbed
+}
+
+// writeRecording replaces the recording file, readable only by the current user
+func writeRecording(path string, recording Recording) error {
+	jsonData, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording: %w", err)
+	}
+	if err := os.WriteFile(path, jsonData, 0600); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// replayTransport answers requests from a recording without using the network.
+// Each recorded exchange is used once, in recorded order, so repeated
+// requests to the same endpoint get their responses in sequence.
+type replayTransport struct {
+	mu        sync.Mutex
+	exchanges []RecordedExchange
+	used      []bool
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, exchange := range t.exchanges {
+		if t.used[i] || exchange.Method != req.Method || exchange.Path != req.URL.RequestURI() {
+			continue
+		}
+		t.used[i] = true
+
+		body, err := decodeBody(exchange.ResponseBody, exchange.ResponseBodyEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recorded response for %s %s: %w", req.Method, req.URL.RequestURI(), err)
+		}
+		header := exchange.ResponseHeader.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+			StatusCode:    exchange.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response left for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// setupSessionTransport prepares recording or replaying from the global flags
+func setupSessionTransport() error {
+	switch {
+	case recordFile != "" && replayFile != "":
+		return fmt.Errorf("--record and --replay cannot be used together")
+	case replayFile != "":
+		content, err := os.ReadFile(replayFile)
+		if err != nil {
+			return fmt.Errorf("failed to read recording: %w", err)
+		}
+
+		var recording Recording
+		if err := json.Unmarshal(content, &recording); err != nil {
+			return fmt.Errorf("failed to parse recording %s: %w", replayFile, err)
+		}
+		sessionTransport = &replayTransport{
+			exchanges: recording.Exchanges,
+			used:      make([]bool, len(recording.Exchanges)),
+		}
+	case recordFile != "":
+		sessionTransport = &recordingTransport{next: newTransport(), path: recordFile}
+	}
+	return nil
+}
+
+// apiTransport returns the transport for API clients: the network, or the
+// recorder or replayer of the current session
+func apiTransport() http.RoundTripper {
+	if sessionTransport != nil {
+		return sessionTransport
+	}
+	return newTransport()
+}
@@ -0,0 +1,537 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// RedirectionHost represents a redirection host configuration
+type RedirectionHost struct {
+	ID                int           `json:"id"`
+	DomainNames       []string      `json:"domain_names"`
+	ForwardHTTPCode   int           `json:"forward_http_code"`
+	ForwardScheme     string        `json:"forward_scheme"`
+	ForwardDomainName string        `json:"forward_domain_name"`
+	PreservePath      bool          `json:"preserve_path"`
+	CertificateID     int           `json:"certificate_id"`
+	SslForced         bool          `json:"ssl_forced"`
+	HSTSEnabled       bool          `json:"hsts_enabled"`
+	HSTSSubdomains    bool          `json:"hsts_subdomains"`
+	HTTP2Support      bool          `json:"http2_support"`
+	BlockExploits     bool          `json:"block_exploits"`
+	AdvancedConfig    string        `json:"advanced_config"`
+	Enabled           bool          `json:"enabled"`
+	CreatedOn         string        `json:"created_on"`
+	ModifiedOn        string        `json:"modified_on"`
+	Meta              ProxyHostMeta `json:"meta"`
+}
+
+// redirectionHTTPCodes are the redirect status codes NPM accepts
+var redirectionHTTPCodes = []int{300, 301, 302, 303, 307, 308}
+
+// tls returns the SSL settings of the redirection host
+func (h RedirectionHost) tls() hostTLS {
+	return hostTLS{
+		CertificateID:  h.CertificateID,
+		SslForced:      h.SslForced,
+		HTTP2Support:   h.HTTP2Support,
+		HSTSEnabled:    h.HSTSEnabled,
+		HSTSSubdomains: h.HSTSSubdomains,
+	}
+}
+
+// setTLS replaces the SSL settings of the redirection host
+func (h *RedirectionHost) setTLS(t hostTLS) {
+	h.CertificateID = t.CertificateID
+	h.SslForced = t.SslForced
+	h.HTTP2Support = t.HTTP2Support
+	h.HSTSEnabled = t.HSTSEnabled
+	h.HSTSSubdomains = t.HSTSSubdomains
+}
+
+// redirectTarget returns where a redirection host sends requests to, with
+// $scheme standing for the scheme of the request like in the nginx config
+func redirectTarget(h RedirectionHost) string {
+	scheme := h.ForwardScheme
+	if scheme == "" || scheme == "auto" {
+		scheme = "$scheme"
+	}
+	target := scheme + "://" + h.ForwardDomainName
+	if h.PreservePath {
+		target += "$request_uri"
+	}
+	return target
+}
+
+// validateRedirectionFlags checks the redirect flags that were given
+func validateRedirectionFlags(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("forward-domain") {
+		forwardDomain, _ := flags.GetString("forward-domain")
+		forwardDomain = strings.TrimSpace(forwardDomain)
+		if forwardDomain == "" {
+			return fmt.Errorf("forward-domain cannot be empty")
+		}
+		if strings.Contains(forwardDomain, "://") {
+			return fmt.Errorf("forward-domain %q must not contain a scheme, use --forward-scheme", forwardDomain)
+		}
+	}
+	if flags.Changed("forward-scheme") {
+		scheme, _ := flags.GetString("forward-scheme")
+		if scheme != "auto" && scheme != "http" && scheme != "https" {
+			return fmt.Errorf("invalid forward-scheme %q, expected auto, http or https", scheme)
+		}
+	}
+	if flags.Changed("http-code") {
+		code, _ := flags.GetInt("http-code")
+		valid := false
+		for _, c := range redirectionHTTPCodes {
+			valid = valid || c == code
+		}
+		if !valid {
+			return fmt.Errorf("invalid http-code %d, expected one of %v", code, redirectionHTTPCodes)
+		}
+	}
+	return nil
+}
+
+// applyRedirectionFlags applies the redirect flags that were given to the host
+func applyRedirectionFlags(cmd *cobra.Command, host *RedirectionHost) {
+	flags := cmd.Flags()
+
+	if flags.Changed("forward-domain") {
+		forwardDomain, _ := flags.GetString("forward-domain")
+		host.ForwardDomainName = strings.TrimSpace(forwardDomain)
+	}
+	if flags.Changed("forward-scheme") {
+		host.ForwardScheme, _ = flags.GetString("forward-scheme")
+	}
+	if flags.Changed("http-code") {
+		host.ForwardHTTPCode, _ = flags.GetInt("http-code")
+	}
+	if flags.Changed("preserve-path") {
+		host.PreservePath, _ = flags.GetBool("preserve-path")
+	}
+	if flags.Changed("block-exploits") {
+		host.BlockExploits, _ = flags.GetBool("block-exploits")
+	}
+}
+
+// GetRedirectionHost fetches a single redirection host by ID
+func (c *APIClient) GetRedirectionHost(id int) (*RedirectionHost, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/redirection-hosts/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("redirection host %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get redirection host, status: %d", resp.StatusCode)
+	}
+
+	var host RedirectionHost
+	if err := decodeJSON(resp.Body, &host); err != nil {
+		return nil, fmt.Errorf("failed to decode redirection host: %w", err)
+	}
+
+	return &host, nil
+}
+
+// ListRedirectionHosts lists all redirection hosts
+func (c *APIClient) ListRedirectionHosts() ([]RedirectionHost, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/redirection-hosts", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list redirection hosts, status: %d", resp.StatusCode)
+	}
+
+	var hosts []RedirectionHost
+	if err := decodeJSON(resp.Body, &hosts); err != nil {
+		return nil, fmt.Errorf("failed to decode redirection hosts: %w", err)
+	}
+
+	return hosts, nil
+}
+
+// UpdateRedirectionHost updates an existing redirection host
+func (c *APIClient) UpdateRedirectionHost(id int, host RedirectionHost) (*RedirectionHost, error) {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal redirection host: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/nginx/redirection-hosts/%d", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update redirection host, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var updatedHost RedirectionHost
+	if err := decodeJSON(resp.Body, &updatedHost); err != nil {
+		return nil, fmt.Errorf("failed to decode updated redirection host: %w", err)
+	}
+
+	return &updatedHost, nil
+}
+
+// CreateRedirectionHost creates a new redirection host
+func (c *APIClient) CreateRedirectionHost(host RedirectionHost) (*RedirectionHost, error) {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal redirection host: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", "/nginx/redirection-hosts", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create redirection host, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var createdHost RedirectionHost
+	if err := decodeJSON(resp.Body, &createdHost); err != nil {
+		return nil, fmt.Errorf("failed to decode created redirection host: %w", err)
+	}
+
+	return &createdHost, nil
+}
+
+// DeleteRedirectionHost deletes a redirection host by ID
+func (c *APIClient) DeleteRedirectionHost(id int) error {
+	resp, err := c.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/nginx/redirection-hosts/%d", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("redirection host %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete redirection host, status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SetRedirectionHostEnabled enables or disables a redirection host without changing its configuration
+func (c *APIClient) SetRedirectionHostEnabled(id int, enabled bool) error {
+	return c.setEnabled("redirection-hosts", "redirection host", id, enabled)
+}
+
+// printRedirectionHost prints the details of a redirection host
+func printRedirectionHost(host RedirectionHost) {
+	fmt.Fprintf(out, "ID: %d\n", host.ID)
+	fmt.Fprintf(out, "Domains: %s\n", strings.Join(host.DomainNames, ", "))
+	fmt.Fprintf(out, "Redirect: %d to %s\n", host.ForwardHTTPCode, redirectTarget(host))
+	fmt.Fprintf(out, "Preserve Path: %t\n", host.PreservePath)
+	fmt.Fprintf(out, "SSL: %s\n", host.tls().status())
+	if host.CertificateID != 0 {
+		fmt.Fprintf(out, "Certificate ID: %d\n", host.CertificateID)
+	}
+	fmt.Fprintf(out, "HTTP/2: %t\n", host.HTTP2Support)
+	fmt.Fprintf(out, "HSTS: %t\n", host.HSTSEnabled)
+	fmt.Fprintf(out, "Block Exploits: %t\n", host.BlockExploits)
+	fmt.Fprintf(out, "Enabled: %t\n", host.Enabled)
+}
+
+var redirectionCmd = &cobra.Command{
+	Use:   "redirection",
+	Short: "Manage redirection hosts",
+}
+
+var redirectionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all redirection hosts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListRedirectionHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list redirection hosts: %w", err)
+		}
+
+		if output == "json" {
+			return writeJSON(append([]RedirectionHost{}, hosts...))
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tDOMAINS\tTARGET\tCODE\tSSL\tENABLED")
+		for _, host := range hosts {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%t\n", host.ID, strings.Join(host.DomainNames, ","), redirectTarget(host),
+				host.ForwardHTTPCode, host.tls().status(), host.Enabled)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var redirectionGetCmd = &cobra.Command{
+	Use:   "get ID",
+	Short: "Show a redirection host",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetRedirectionHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(host)
+		}
+		printRedirectionHost(*host)
+		return nil
+	},
+}
+
+var redirectionCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a redirection host",
+	Long: `Create a redirection host that redirects all requests for its domains to
+--forward-domain. With --forward-scheme auto, the default, the scheme of the
+request is kept. --preserve-path keeps the path and query of the request.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
+		forwardDomain, _ := cmd.Flags().GetString("forward-domain")
+		disabled, _ := cmd.Flags().GetBool("disabled")
+
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
+		}
+		if len(domains) == 0 || strings.TrimSpace(forwardDomain) == "" {
+			return fmt.Errorf("domain and forward-domain are required")
+		}
+		if err := validateRedirectionFlags(cmd); err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host := RedirectionHost{
+			DomainNames:     domains,
+			ForwardScheme:   "auto",
+			ForwardHTTPCode: 301,
+			BlockExploits:   true,
+			Enabled:         !disabled,
+		}
+		applyRedirectionFlags(cmd, &host)
+
+		tls := host.tls()
+		if err := applyTLSFlags(cmd, client, domains, &tls); err != nil {
+			return err
+		}
+		host.setTLS(tls)
+
+		if err := applyDuplicateDomainCheck(cmd, client, domains, false); err != nil {
+			return err
+		}
+
+		createdHost, err := client.CreateRedirectionHost(host)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully created redirection host with ID: %d\n", createdHost.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(createdHost.DomainNames, ", "))
+		fmt.Fprintf(out, "Redirect: %d to %s\n", createdHost.ForwardHTTPCode, redirectTarget(*createdHost))
+		fmt.Fprintf(out, "SSL: %s\n", createdHost.tls().status())
+		return nil
+	},
+}
+
+var redirectionUpdateCmd = &cobra.Command{
+	Use:   "update ID",
+	Short: "Update a redirection host",
+	Long:  `Update a redirection host. Only the settings given as flags are changed.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
+
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("domain") && len(domains) == 0 {
+			return fmt.Errorf("domain cannot be empty")
+		}
+		if err := validateRedirectionFlags(cmd); err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetRedirectionHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		if len(domains) > 0 {
+			// Only the new domains can clash with other hosts
+			var added []string
+			for _, domain := range domains {
+				if hostDomainIndex(ProxyHost{DomainNames: host.DomainNames}, domain) < 0 {
+					added = append(added, domain)
+				}
+			}
+			if err := applyDuplicateDomainCheck(cmd, client, added, false); err != nil {
+				return err
+			}
+			host.DomainNames = domains
+		}
+		applyRedirectionFlags(cmd, host)
+
+		if tlsFlagsChanged(cmd) {
+			tls := host.tls()
+			if err := applyTLSFlags(cmd, client, host.DomainNames, &tls); err != nil {
+				return err
+			}
+			host.setTLS(tls)
+		}
+
+		updatedHost, err := client.UpdateRedirectionHost(host.ID, *host)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully updated redirection host with ID: %d\n", updatedHost.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(updatedHost.DomainNames, ", "))
+		fmt.Fprintf(out, "Redirect: %d to %s\n", updatedHost.ForwardHTTPCode, redirectTarget(*updatedHost))
+		fmt.Fprintf(out, "SSL: %s\n", updatedHost.tls().status())
+		return nil
+	},
+}
+
+var redirectionDeleteCmd = &cobra.Command{
+	Use:          "delete ID...",
+	Short:        "Delete redirection hosts",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		yes, _ := cmd.Flags().GetBool("yes")
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Delete %d redirection hosts?", len(ids))) {
+			return fmt.Errorf("aborted")
+		}
+
+		var result BatchResult
+		for _, id := range ids {
+			err := client.DeleteRedirectionHost(id)
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to delete redirection host %d: %v\n", id, err)
+				continue
+			}
+			fmt.Fprintf(out, "Deleted redirection host %d\n", id)
+		}
+
+		return result.Err()
+	},
+}
+
+var redirectionEnableCmd = &cobra.Command{
+	Use:          "enable ID...",
+	Short:        "Enable redirection hosts",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleHosts("redirection host", (*APIClient).SetRedirectionHostEnabled, true),
+}
+
+var redirectionDisableCmd = &cobra.Command{
+	Use:          "disable ID...",
+	Short:        "Disable redirection hosts",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleHosts("redirection host", (*APIClient).SetRedirectionHostEnabled, false),
+}
+
+// addRedirectionFlags registers the flags shared by redirection create and update
+func addRedirectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("domain", nil, "Domain names to redirect (repeatable or comma separated)")
+	cmd.Flags().String("forward-domain", "", "Domain to redirect to, without scheme")
+	cmd.Flags().String("forward-scheme", "auto", "Scheme to redirect to (auto, http or https)")
+	cmd.Flags().Int("http-code", 301, "HTTP status code of the redirect (301 permanent, 302 temporary)")
+	cmd.Flags().Bool("preserve-path", false, "Keep the path and query of the request")
+	cmd.Flags().Bool("block-exploits", true, "Block common exploits")
+	cmd.Flags().Bool("force", false, "Allow domains that are already served by another host")
+	addTLSFlags(cmd)
+}
+
+func init() {
+	addRedirectionFlags(redirectionCreateCmd)
+	redirectionCreateCmd.Flags().Bool("disabled", false, "Create the redirection host disabled")
+	addRedirectionFlags(redirectionUpdateCmd)
+
+	redirectionDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	redirectionCmd.AddCommand(redirectionListCmd)
+	redirectionCmd.AddCommand(redirectionGetCmd)
+	redirectionCmd.AddCommand(redirectionCreateCmd)
+	redirectionCmd.AddCommand(redirectionUpdateCmd)
+	redirectionCmd.AddCommand(redirectionDeleteCmd)
+	redirectionCmd.AddCommand(redirectionEnableCmd)
+	redirectionCmd.AddCommand(redirectionDisableCmd)
+	rootCmd.AddCommand(redirectionCmd)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// findCertificateByName returns the certificate whose nice name or one of
+// whose domains is name, like "*.example.com"
+func findCertificateByName(certs []Certificate, name string) (*Certificate, error) {
+	var matches []Certificate
+	for _, cert := range certs {
+		if strings.EqualFold(cert.NiceName, name) {
+			matches = append(matches, cert)
+			continue
+		}
+		for _, domain := range cert.DomainNames {
+			if strings.EqualFold(domain, name) {
+				matches = append(matches, cert)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no certificate named %q or for domain %q", name, name)
+	case 1:
+		return &matches[0], nil
+	}
+
+	var lines []string
+	for _, cert := range matches {
+		lines = append(lines, fmt.Sprintf("  %d %s %v", cert.ID, cert.NiceName, cert.DomainNames))
+	}
+	return nil, fmt.Errorf("%q matches %d certificates, choose one with --certificate-id:\n%s",
+		name, len(matches), strings.Join(lines, "\n"))
+}
+
+// findAccessListByName returns the access list with the given name
+func findAccessListByName(lists []AccessList, name string) (*AccessList, error) {
+	var matches []AccessList
+	for _, list := range lists {
+		if strings.EqualFold(list.Name, name) {
+			matches = append(matches, list)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no access list named %q", name)
+	case 1:
+		return &matches[0], nil
+	}
+
+	var ids []string
+	for _, list := range matches {
+		ids = append(ids, fmt.Sprint(list.ID))
+	}
+	return nil, fmt.Errorf("%d access lists are named %q (IDs %s), choose one with --access-list-id",
+		len(matches), name, strings.Join(ids, ", "))
+}
+
+// addAccessListFlags registers the flags that select the access list of a host
+func addAccessListFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("access-list-id", 0, "ID of the access list restricting the proxy host, 0 for public")
+	cmd.Flags().String("access-list", "", "Name of the access list restricting the proxy host")
+	cmd.MarkFlagsMutuallyExclusive("access-list-id", "access-list")
+}
+
+// applyAccessListIDFlag applies --access-list-id when it was given
+func applyAccessListIDFlag(cmd *cobra.Command, host *ProxyHost) {
+	if cmd.Flags().Changed("access-list-id") {
+		host.AccessListID, _ = cmd.Flags().GetInt("access-list-id")
+	}
+}
+
+// resolveReferenceFlags looks up the certificate and access list given by
+// name with --certificate and --access-list and sets their IDs on the host
+func resolveReferenceFlags(cmd *cobra.Command, client *APIClient, host *ProxyHost) error {
+	flags := cmd.Flags()
+
+	if name, _ := flags.GetString("certificate"); name != "" {
+		certs, err := client.ListCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+		cert, err := findCertificateByName(certs, name)
+		if err != nil {
+			return err
+		}
+		host.CertificateID = cert.ID
+
+		if uncovered := uncoveredDomains(host.DomainNames, cert.DomainNames); len(uncovered) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: certificate %d %v does not cover %s\n", cert.ID, cert.DomainNames, strings.Join(uncovered, ", "))
+		}
+	}
+
+	if name, _ := flags.GetString("access-list"); name != "" {
+		lists, err := client.ListAccessLists()
+		if err != nil {
+			return fmt.Errorf("failed to list access lists: %w", err)
+		}
+		list, err := findAccessListByName(lists, name)
+		if err != nil {
+			return err
+		}
+		host.AccessListID = list.ID
+	}
+
+	return nil
+}
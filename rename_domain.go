@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// checkDomainResolves fails when a domain has no DNS records. Wildcards can't
+// be looked up and are skipped.
+func checkDomainResolves(domain string) error {
+	if strings.HasPrefix(domain, "*.") {
+		fmt.Fprintf(os.Stderr, "Warning: cannot check DNS of wildcard domain %s, skipping\n", domain)
+		return nil
+	}
+
+	addrs, err := net.LookupHost(domain)
+	if err != nil {
+		return fmt.Errorf("%s does not resolve: %w", domain, err)
+	}
+	fmt.Fprintf(out, "%s resolves to %s\n", domain, strings.Join(addrs, ", "))
+	return nil
+}
+
+var renameDomainCmd = &cobra.Command{
+	Use:   "rename-domain ID OLD NEW",
+	Short: "Replace a domain name of a proxy host with another",
+	Long: `Replace one domain name of a proxy host with a new one, keeping its position
+and all other settings of the host.
+
+The new name is validated and must not be served by another host yet. With
+--check-dns it must also resolve before the host is changed.`,
+	Args:         cobra.ExactArgs(3),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args[:1])
+		if err != nil {
+			return err
+		}
+		id := ids[0]
+		oldDomain := strings.TrimSpace(args[1])
+		newDomain := strings.ToLower(strings.TrimSpace(args[2]))
+		if err := validateDomainName(newDomain); err != nil {
+			return err
+		}
+		if strings.EqualFold(oldDomain, newDomain) {
+			return fmt.Errorf("old and new domain are the same")
+		}
+
+		if checkDNS, _ := cmd.Flags().GetBool("check-dns"); checkDNS {
+			if err := checkDomainResolves(newDomain); err != nil {
+				return err
+			}
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(id)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy host: %w", err)
+		}
+
+		index := hostDomainIndex(*host, oldDomain)
+		if index < 0 {
+			return fmt.Errorf("proxy host %d does not have domain %s", id, oldDomain)
+		}
+		if hostDomainIndex(*host, newDomain) >= 0 {
+			return fmt.Errorf("proxy host %d already has domain %s", id, newDomain)
+		}
+		if err := applyDuplicateDomainCheck(cmd, client, []string{newDomain}, false); err != nil {
+			return err
+		}
+
+		host.DomainNames[index] = newDomain
+
+		if host.CertificateID != 0 {
+			cert, err := client.GetCertificate(host.CertificateID)
+			if err != nil {
+				return fmt.Errorf("failed to check the certificate of proxy host %d: %w", id, err)
+			}
+			if len(uncoveredDomains([]string{newDomain}, cert.DomainNames)) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: certificate %d does not cover %s, HTTPS requests for it will get a certificate error\n", cert.ID, newDomain)
+			}
+		}
+
+		updatedHost, err := client.UpdateProxyHost(id, *host)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Renamed %s to %s on proxy host %d\n", oldDomain, newDomain, updatedHost.ID)
+		fmt.Fprintf(out, "Domains: %s\n", strings.Join(updatedHost.DomainNames, ", "))
+
+		return nil
+	},
+}
+
+func init() {
+	renameDomainCmd.Flags().Bool("check-dns", false, "Require the new domain to resolve before renaming")
+	renameDomainCmd.Flags().Bool("force", false, "Rename even if the new domain is served by another host")
+
+	rootCmd.AddCommand(renameDomainCmd)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// findOrphanedCertificateRefs returns the hosts referencing certificates that don't exist
+func findOrphanedCertificateRefs(hosts []ProxyHost, certs []Certificate) []ProxyHost {
+	existing := make(map[int]bool, len(certs))
+	for _, cert := range certs {
+		existing[cert.ID] = true
+	}
+
+	var orphans []ProxyHost
+	for _, host := range hosts {
+		if host.CertificateID != 0 && !existing[host.CertificateID] {
+			orphans = append(orphans, host)
+		}
+	}
+	return orphans
+}
+
+// repairCertificateRef clears or reissues the missing certificate of a host
+// and returns a description of what was done
+func repairCertificateRef(client *APIClient, host ProxyHost, reissue bool, email string) (string, error) {
+	if !reissue {
+		host.CertificateID = 0
+		host.SslForced = false
+		if _, err := client.UpdateProxyHost(host.ID, host); err != nil {
+			return "", err
+		}
+		return "cleared certificate, SSL disabled", nil
+	}
+
+	cert, err := client.CreateCertificate(Certificate{
+		Provider:    "letsencrypt",
+		NiceName:    primaryDomain(host),
+		DomainNames: host.DomainNames,
+		Meta: CertificateMeta{
+			LetsEncryptEmail: email,
+			LetsEncryptAgree: true,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	host.CertificateID = cert.ID
+	if _, err := client.UpdateProxyHost(host.ID, host); err != nil {
+		return "", fmt.Errorf("certificate %d was issued but binding it failed: %w", cert.ID, err)
+	}
+	return fmt.Sprintf("reissued as certificate %d", cert.ID), nil
+}
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Find and fix inconsistencies between NPM objects",
+}
+
+var repairCertificatesCmd = &cobra.Command{
+	Use:          "certificates",
+	Short:        "Fix proxy hosts that reference deleted certificates",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		apply, _ := cmd.Flags().GetBool("apply")
+		reissue, _ := cmd.Flags().GetBool("reissue")
+		email, _ := cmd.Flags().GetString("email")
+		if reissue && email == "" {
+			return fmt.Errorf("email is required with --reissue")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		hosts, err := client.ListProxyHosts()
+		if err != nil {
+			return fmt.Errorf("failed to list proxy hosts: %w", err)
+		}
+
+		certs, err := client.ListCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+
+		orphans := findOrphanedCertificateRefs(hosts, certs)
+		if len(orphans) == 0 {
+			fmt.Fprintf(out, "All %d proxy hosts reference existing certificates\n", len(hosts))
+			return nil
+		}
+
+		failures := 0
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "HOST ID\tDOMAINS\tMISSING CERTIFICATE\tACTION")
+		for _, host := range orphans {
+			var action string
+			switch {
+			case !apply && reissue:
+				action = "would reissue certificate"
+			case !apply:
+				action = "would clear certificate"
+			default:
+				if action, err = repairCertificateRef(client, host, reissue, email); err != nil {
+					action = "failed: " + err.Error()
+					failures++
+				}
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", host.ID, strings.Join(host.DomainNames, ","), host.CertificateID, action)
+		}
+		w.Flush()
+
+		if !apply {
+			fmt.Fprintf(out, "\nDry run: found %d proxy hosts with missing certificates, use --apply to repair them\n", len(orphans))
+			return nil
+		}
+		if failures > 0 {
+			return fmt.Errorf("failed to repair %d of %d proxy hosts", failures, len(orphans))
+		}
+
+		fmt.Fprintf(out, "\nRepaired %d proxy hosts\n", len(orphans))
+		return nil
+	},
+}
+
+func init() {
+	repairCertificatesCmd.Flags().Bool("apply", false, "Make the changes instead of only reporting them")
+	repairCertificatesCmd.Flags().Bool("reissue", false, "Request a new Let's Encrypt certificate instead of disabling SSL")
+	repairCertificatesCmd.Flags().String("email", "", "Let's Encrypt account email for --reissue")
+
+	repairCmd.AddCommand(repairCertificatesCmd)
+	rootCmd.AddCommand(repairCmd)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// searchMatch is a field of a host that matches a search pattern
+type searchMatch struct {
+	Type  string `json:"type"`
+	ID    int    `json:"id"`
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// searchable is a field of a host that search looks at
+type searchable struct {
+	Field string
+	Value string
+}
+
+// proxyHostSearchables returns the searchable fields of a proxy host
+func proxyHostSearchables(host ProxyHost) []searchable {
+	var fields []searchable
+	for _, domain := range host.DomainNames {
+		fields = append(fields, searchable{"domain_names", domain})
+	}
+	fields = append(fields, searchable{"forward_host", host.ForwardHost})
+	fields = append(fields, searchable{"advanced_config", host.AdvancedConfig})
+	for _, location := range host.Locations {
+		fields = append(fields, searchable{"locations " + location.Path + " forward_host", location.ForwardHost})
+		fields = append(fields, searchable{"locations " + location.Path + " advanced_config", location.AdvancedConfig})
+	}
+	return fields
+}
+
+// redirectionHostSearchables returns the searchable fields of a redirection host
+func redirectionHostSearchables(host RedirectionHost) []searchable {
+	var fields []searchable
+	for _, domain := range host.DomainNames {
+		fields = append(fields, searchable{"domain_names", domain})
+	}
+	fields = append(fields, searchable{"forward_domain_name", host.ForwardDomainName})
+	fields = append(fields, searchable{"advanced_config", host.AdvancedConfig})
+	return fields
+}
+
+// deadHostSearchables returns the searchable fields of a 404 host
+func deadHostSearchables(host DeadHost) []searchable {
+	var fields []searchable
+	for _, domain := range host.DomainNames {
+		fields = append(fields, searchable{"domain_names", domain})
+	}
+	fields = append(fields, searchable{"advanced_config", host.AdvancedConfig})
+	return fields
+}
+
+// streamSearchables returns the searchable fields of a stream
+func streamSearchables(stream Stream) []searchable {
+	return []searchable{
+		{"incoming_port", strconv.Itoa(stream.IncomingPort)},
+		{"forwarding_host", stream.ForwardingHost},
+	}
+}
+
+// matchSearchables returns the fields matching the pattern. For multi-line
+// values like advanced configs, each matching line is a match of its own.
+func matchSearchables(hostType string, id int, fields []searchable, pattern *regexp.Regexp) []searchMatch {
+	var matches []searchMatch
+	for _, field := range fields {
+		for _, line := range strings.Split(field.Value, "\n") {
+			if line != "" && pattern.MatchString(line) {
+				matches = append(matches, searchMatch{hostType, id, field.Field, strings.TrimSpace(line)})
+			}
+		}
+	}
+	return matches
+}
+
+// searchHosts fetches all host types concurrently and returns the fields
+// matching the pattern
+func searchHosts(client *APIClient, pattern *regexp.Regexp) ([]searchMatch, error) {
+	var (
+		wg                                           sync.WaitGroup
+		proxyHosts                                   []ProxyHost
+		redirectionHosts                             []RedirectionHost
+		deadHosts                                    []DeadHost
+		streams                                      []Stream
+		proxyErr, redirectionErr, deadErr, streamErr error
+	)
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		proxyHosts, proxyErr = client.ListProxyHosts()
+	}()
+	go func() {
+		defer wg.Done()
+		redirectionHosts, redirectionErr = client.ListRedirectionHosts()
+	}()
+	go func() {
+		defer wg.Done()
+		deadHosts, deadErr = client.ListDeadHosts()
+	}()
+	go func() {
+		defer wg.Done()
+		streams, streamErr = client.ListStreams()
+	}()
+	wg.Wait()
+
+	if proxyErr != nil {
+		return nil, fmt.Errorf("failed to list proxy hosts: %w", proxyErr)
+	}
+	if redirectionErr != nil {
+		return nil, fmt.Errorf("failed to list redirection hosts: %w", redirectionErr)
+	}
+	if deadErr != nil {
+		return nil, fmt.Errorf("failed to list 404 hosts: %w", deadErr)
+	}
+	if streamErr != nil {
+		return nil, fmt.Errorf("failed to list streams: %w", streamErr)
+	}
+
+	var matches []searchMatch
+	for _, host := range proxyHosts {
+		matches = append(matches, matchSearchables("proxy host", host.ID, proxyHostSearchables(host), pattern)...)
+	}
+	for _, host := range redirectionHosts {
+		matches = append(matches, matchSearchables("redirection host", host.ID, redirectionHostSearchables(host), pattern)...)
+	}
+	for _, host := range deadHosts {
+		matches = append(matches, matchSearchables("404 host", host.ID, deadHostSearchables(host), pattern)...)
+	}
+	for _, stream := range streams {
+		matches = append(matches, matchSearchables("stream", stream.ID, streamSearchables(stream), pattern)...)
+	}
+	return matches, nil
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search PATTERN",
+	Short: "Search all host types for a domain, forward target or config snippet",
+	Long: `Search proxy hosts, redirection hosts, 404 hosts and streams for PATTERN.
+
+Domain names, forward hosts and advanced configs (including those of custom
+locations) are searched, as well as the incoming ports of streams. PATTERN is a
+case-insensitive substring, or a regular expression with --regex. Every match
+is printed with the host type, ID and field.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		useRegex, _ := cmd.Flags().GetBool("regex")
+		expr := regexp.QuoteMeta(args[0])
+		if useRegex {
+			expr = args[0]
+		}
+		pattern, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		matches, err := searchHosts(client, pattern)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(append([]searchMatch{}, matches...))
+		}
+		if len(matches) == 0 {
+			fmt.Fprintf(out, "No matches for %q\n", args[0])
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tID\tFIELD\tVALUE")
+		for _, match := range matches {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", match.Type, match.ID, match.Field, match.Value)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+func init() {
+	searchCmd.Flags().Bool("regex", false, "Treat PATTERN as a regular expression")
+
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// parseFieldAssignment parses a field=value argument of the set command. The
+// value is interpreted according to the current type of the field: strings are
+// taken literally, lists may be given comma separated and everything else must
+// be valid JSON, like true or 8443.
+func parseFieldAssignment(assignment string, current map[string]json.RawMessage) (string, json.RawMessage, error) {
+	field, value, ok := strings.Cut(assignment, "=")
+	field = strings.TrimSpace(field)
+	if !ok || field == "" {
+		return "", nil, fmt.Errorf("invalid assignment %q, expected field=value", assignment)
+	}
+
+	raw, known := current[field]
+	if !known {
+		return "", nil, fmt.Errorf("unknown or read-only field %q", field)
+	}
+
+	trimmed := strings.TrimSpace(string(raw))
+	switch {
+	case strings.HasPrefix(trimmed, `"`):
+		encoded, err := json.Marshal(value)
+		return field, encoded, err
+	case json.Valid([]byte(value)):
+		return field, json.RawMessage(value), nil
+	case strings.HasPrefix(trimmed, "[") || trimmed == "null":
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		encoded, err := json.Marshal(items)
+		return field, encoded, err
+	default:
+		return "", nil, fmt.Errorf("invalid value %q for field %s, expected JSON like %s", value, field, trimmed)
+	}
+}
+
+var setCmd = &cobra.Command{
+	Use:   "set ID FIELD=VALUE...",
+	Short: "Change single fields of a proxy host",
+	Long: `Change single fields of a proxy host, for example:
+
+  set 12 ssl_forced=true forward_port=8443
+
+FIELD is a proxy host JSON field as shown by get --output json. String values
+are taken literally, list values like domain_names may be comma separated, and
+numbers and booleans are given as in JSON. The host is fetched, changed and
+sent back, and the result is validated like in the edit command.`,
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args[:1])
+		if err != nil {
+			return err
+		}
+		for _, assignment := range args[1:] {
+			if !strings.Contains(assignment, "=") {
+				return fmt.Errorf("invalid assignment %q, expected field=value", assignment)
+			}
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(ids[0])
+		if err != nil {
+			return err
+		}
+
+		current, err := editableFields(*host)
+		if err != nil {
+			return err
+		}
+
+		changes := make(map[string]json.RawMessage)
+		var fields []string
+		for _, assignment := range args[1:] {
+			field, value, err := parseFieldAssignment(assignment, current)
+			if err != nil {
+				return err
+			}
+			if _, seen := changes[field]; !seen {
+				fields = append(fields, field)
+			}
+			changes[field] = value
+		}
+
+		updated, err := mergeProxyHostFields(*host, changes)
+		if err != nil {
+			return err
+		}
+		if err := validateProxyHost(updated); err != nil {
+			return err
+		}
+
+		updatedHost, err := client.UpdateProxyHost(host.ID, updated)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully updated proxy host with ID: %d\n", updatedHost.ID)
+		for _, field := range fields {
+			fmt.Fprintf(out, "%s: %s -> %s\n", field, current[field], changes[field])
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+}
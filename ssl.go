@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// sslStatus describes how a proxy host serves HTTPS
+func sslStatus(host ProxyHost) string {
+	switch {
+	case host.CertificateID == 0 && host.SslForced:
+		return "forced, but no certificate"
+	case host.CertificateID == 0:
+		return "none"
+	case host.SslForced:
+		return "forced"
+	default:
+		return "available, not forced"
+	}
+}
+
+// addSSLFlags registers the flags controlling certificate and SSL redirect
+func addSSLFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("certificate-id", 0, "ID of the certificate to use for HTTPS")
+	cmd.Flags().String("certificate", "", "Name or domain of the certificate to use for HTTPS, like *.example.com")
+	cmd.Flags().Bool("ssl-forced", false, "Redirect HTTP requests to HTTPS")
+	cmd.Flags().Bool("no-ssl-redirect", false, "Serve both HTTP and HTTPS without redirecting")
+	cmd.MarkFlagsMutuallyExclusive("ssl-forced", "no-ssl-redirect")
+	cmd.MarkFlagsMutuallyExclusive("certificate-id", "certificate")
+}
+
+// applySSLFlags applies the SSL flags that were given on the command line to
+// the host. Flags that weren't given leave the host untouched, so SslForced
+// never changes unless asked for. A certificate given by name with
+// --certificate is looked up later by resolveReferenceFlags, one requested
+// with --ssl of create is issued after the host is created.
+func applySSLFlags(cmd *cobra.Command, host *ProxyHost) error {
+	flags := cmd.Flags()
+	certificateChanged := flags.Changed("certificate-id") || flags.Changed("certificate")
+	newCertificate, _ := flags.GetBool("ssl")
+
+	if newCertificate {
+		host.SslForced = true
+	}
+
+	if flags.Changed("certificate-id") {
+		host.CertificateID, _ = flags.GetInt("certificate-id")
+	}
+	if flags.Changed("ssl-forced") {
+		host.SslForced, _ = flags.GetBool("ssl-forced")
+	}
+	if noRedirect, _ := flags.GetBool("no-ssl-redirect"); noRedirect {
+		host.SslForced = false
+	}
+
+	if host.SslForced && host.CertificateID == 0 && !flags.Changed("certificate") && !newCertificate {
+		return fmt.Errorf("--ssl-forced requires a certificate, use --certificate-id")
+	}
+
+	// A certificate without an explicit choice about redirecting is easy to
+	// get wrong, so say what will happen
+	if certificateChanged && (host.CertificateID != 0 || flags.Changed("certificate")) && !host.SslForced &&
+		!flags.Changed("ssl-forced") && !flags.Changed("no-ssl-redirect") {
+		fmt.Fprintln(os.Stderr, "Warning: certificate assigned but SSL is not forced, HTTP requests will not be redirected to HTTPS.")
+		fmt.Fprintln(os.Stderr, "Use --ssl-forced to redirect or --no-ssl-redirect to serve both HTTP and HTTPS.")
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of status --exit-code
+const (
+	exitHostsOffline       = 10
+	exitCertificateExpired = 11
+	exitOfflineAndExpired  = 12
+)
+
+// hostStatus describes the health of a single proxy host
+type hostStatus struct {
+	Host        ProxyHost
+	Severity    int
+	Problem     string
+	Offline     bool
+	CertExpired bool
+}
+
+// evaluateHostStatus compares the enabled state of a host with the nginx
+// online status and checks that its certificate hasn't expired
+func evaluateHostStatus(host ProxyHost, certsByID map[int]Certificate, now time.Time) hostStatus {
+	status := hostStatus{Host: host}
+	var problems []string
+
+	if host.Enabled && !host.Meta.NginxOnline {
+		status.Offline = true
+		problem := "enabled but offline"
+		if host.Meta.NginxErr != "" {
+			problem += ": " + host.Meta.NginxErr
+		}
+		problems = append(problems, problem)
+	}
+
+	if cert, ok := certsByID[host.CertificateID]; ok {
+		if expiry, err := cert.Expiry(); err == nil && expiry.Before(now) {
+			status.CertExpired = true
+			problems = append(problems, fmt.Sprintf("certificate %d expired on %s", cert.ID, expiry.Format(time.DateOnly)))
+		}
+	}
+
+	if len(problems) > 0 {
+		status.Severity = 2
+		status.Problem = strings.Join(problems, "; ")
+	}
+
+	return status
+}
+
+// fetchHostStatuses evaluates the status of all proxy hosts of an instance
+func fetchHostStatuses(client *APIClient) ([]hostStatus, error) {
+	hosts, err := client.ListProxyHosts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proxy hosts: %w", err)
+	}
+
+	// Without certificates only the expiry check is lost, so the online
+	// status of the hosts is still shown
+	certs, err := client.ListCertificates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to list certificates of %s, certificate expiry is not checked: %v\n", client.BaseURL, err)
+	}
+
+	certsByID := make(map[int]Certificate, len(certs))
+	for _, cert := range certs {
+		certsByID[cert.ID] = cert
+	}
+
+	now := time.Now()
+	statuses := make([]hostStatus, 0, len(hosts))
+	for _, host := range hosts {
+		statuses = append(statuses, evaluateHostStatus(host, certsByID, now))
+	}
+	return statuses, nil
+}
+
+// profileHostStatus is the status of a proxy host on a named profile
+type profileHostStatus struct {
+	hostStatus
+	Profile string
+}
+
+// hostStatusOutput is the JSON output of status for one proxy host
+type hostStatusOutput struct {
+	ID          int      `json:"id"`
+	DomainNames []string `json:"domain_names"`
+	Enabled     bool     `json:"enabled"`
+	Online      bool     `json:"online"`
+	Offline     bool     `json:"offline"`
+	CertExpired bool     `json:"certificate_expired"`
+	Problem     string   `json:"problem,omitempty"`
+}
+
+// writeStatusJSON prints the statuses as JSON, grouped by profile like the
+// other commands with --all-profiles
+func writeStatusJSON(results []profileResult[hostStatus], statuses []profileHostStatus, allProfiles bool) error {
+	byProfile := make(map[string][]hostStatusOutput)
+	for _, status := range statuses {
+		byProfile[status.Profile] = append(byProfile[status.Profile], hostStatusOutput{
+			ID:          status.Host.ID,
+			DomainNames: status.Host.DomainNames,
+			Enabled:     status.Host.Enabled,
+			Online:      status.Host.Meta.NginxOnline,
+			Offline:     status.Offline,
+			CertExpired: status.CertExpired,
+			Problem:     status.Problem,
+		})
+	}
+
+	if !allProfiles {
+		return writeJSON(append([]hostStatusOutput{}, byProfile[""]...))
+	}
+
+	outputs := make([]profileOutput, 0, len(results))
+	for _, result := range results {
+		profileOut := profileOutput{Profile: result.Profile}
+		if result.Err != nil {
+			profileOut.Error = result.Err.Error()
+		} else {
+			profileOut.Items = append([]hostStatusOutput{}, byProfile[result.Profile]...)
+		}
+		outputs = append(outputs, profileOut)
+	}
+	return writeJSON(outputs)
+}
+
+var statusCmd = &cobra.Command{
+	Use:          "status",
+	Short:        "Show enabled and online status of all proxy hosts",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		problemsOnly, _ := cmd.Flags().GetBool("problems-only")
+		exitCode, _ := cmd.Flags().GetBool("exit-code")
+
+		allProfiles, err := checkAllProfiles(cmd)
+		if err != nil {
+			return err
+		}
+
+		var results []profileResult[hostStatus]
+		if allProfiles {
+			if results, err = fanOutProfiles(fetchHostStatuses); err != nil {
+				return err
+			}
+		} else {
+			client, err := newAuthenticatedClient()
+			if err != nil {
+				return err
+			}
+
+			hostStatuses, err := fetchHostStatuses(client)
+			if err != nil {
+				return err
+			}
+			results = []profileResult[hostStatus]{{Items: hostStatuses}}
+		}
+
+		var statuses []profileHostStatus
+		problems, total, offline, expired := 0, 0, 0, 0
+		for _, result := range results {
+			total += len(result.Items)
+			for _, status := range result.Items {
+				if status.Offline {
+					offline++
+				}
+				if status.CertExpired {
+					expired++
+				}
+				if status.Severity > 0 {
+					problems++
+				} else if problemsOnly {
+					continue
+				}
+				statuses = append(statuses, profileHostStatus{hostStatus: status, Profile: result.Profile})
+			}
+		}
+
+		// Most severe problems first, then by profile and ID
+		sort.SliceStable(statuses, func(i, j int) bool {
+			if statuses[i].Severity != statuses[j].Severity {
+				return statuses[i].Severity > statuses[j].Severity
+			}
+			if statuses[i].Profile != statuses[j].Profile {
+				return statuses[i].Profile < statuses[j].Profile
+			}
+			return statuses[i].Host.ID < statuses[j].Host.ID
+		})
+
+		if output == "json" {
+			if err := writeStatusJSON(results, statuses, allProfiles); err != nil {
+				return err
+			}
+		} else {
+			printHostStatuses(statuses, allProfiles)
+		}
+
+		if allProfiles {
+			if err := reportProfileErrors(results); err != nil {
+				return err
+			}
+		}
+
+		if problems == 0 {
+			if output != "json" {
+				fmt.Fprintf(out, "\nAll %d proxy hosts are healthy\n", total)
+			}
+			return nil
+		}
+
+		err = fmt.Errorf("%d of %d proxy hosts have problems", problems, total)
+		if !exitCode {
+			return err
+		}
+
+		if output != "json" {
+			fmt.Fprintf(out, "\nSummary: %d of %d proxy hosts have problems, %d offline, %d with an expired certificate\n", problems, total, offline, expired)
+		}
+		switch {
+		case offline > 0 && expired > 0:
+			return &exitCodeError{Code: exitOfflineAndExpired, Err: err}
+		case expired > 0:
+			return &exitCodeError{Code: exitCertificateExpired, Err: err}
+		default:
+			return &exitCodeError{Code: exitHostsOffline, Err: err}
+		}
+	},
+}
+
+// printHostStatuses prints the statuses as a table
+func printHostStatuses(statuses []profileHostStatus, allProfiles bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if allProfiles {
+		fmt.Fprint(w, "PROFILE\t")
+	}
+	fmt.Fprintln(w, "ID\tDOMAINS\tENABLED\tONLINE\tPROBLEM")
+	for _, status := range statuses {
+		problem := status.Problem
+		if problem == "" {
+			problem = "-"
+		}
+		if allProfiles {
+			fmt.Fprintf(w, "%s\t", status.Profile)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%t\t%t\t%s\n",
+			status.Host.ID,
+			strings.Join(status.Host.DomainNames, ","),
+			status.Host.Enabled,
+			status.Host.Meta.NginxOnline,
+			problem,
+		)
+	}
+	w.Flush()
+}
+
+func init() {
+	statusCmd.Flags().Bool("problems-only", false, "Only show proxy hosts with problems")
+	statusCmd.Flags().Bool("all-profiles", false, "Show the proxy hosts of all configured profiles")
+	statusCmd.Flags().Bool("exit-code", false, "Exit with 10 if hosts are offline, 11 if certificates expired, 12 if both")
+
+	rootCmd.AddCommand(statusCmd)
+}
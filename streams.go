@@ -0,0 +1,510 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// Stream represents a TCP/UDP stream, which forwards a port without HTTP processing
+type Stream struct {
+	ID             int           `json:"id"`
+	IncomingPort   int           `json:"incoming_port"`
+	ForwardingHost string        `json:"forwarding_host"`
+	ForwardingPort int           `json:"forwarding_port"`
+	TCPForwarding  bool          `json:"tcp_forwarding"`
+	UDPForwarding  bool          `json:"udp_forwarding"`
+	Enabled        bool          `json:"enabled"`
+	CreatedOn      string        `json:"created_on"`
+	ModifiedOn     string        `json:"modified_on"`
+	Meta           ProxyHostMeta `json:"meta"`
+}
+
+// streamProtocols describes which protocols a stream forwards
+func streamProtocols(stream Stream) string {
+	var protocols []string
+	if stream.TCPForwarding {
+		protocols = append(protocols, "tcp")
+	}
+	if stream.UDPForwarding {
+		protocols = append(protocols, "udp")
+	}
+	if len(protocols) == 0 {
+		return "none"
+	}
+	return strings.Join(protocols, "+")
+}
+
+// checkStreamPort fails when the incoming port of stream is already taken by
+// NPM itself or by another stream forwarding one of the same protocols. NPM
+// only answers those with a 500 once nginx fails to start listening. Disabled
+// streams count too, as enabling them would fail.
+func checkStreamPort(streams []Stream, stream Stream) error {
+	if npmPorts(apiURL)[stream.IncomingPort] && stream.TCPForwarding {
+		use := "its admin interface"
+		switch stream.IncomingPort {
+		case 80:
+			use = "HTTP of the proxy hosts"
+		case 443:
+			use = "HTTPS of the proxy hosts"
+		}
+		return fmt.Errorf("incoming port %d/tcp is used by NPM itself for %s", stream.IncomingPort, use)
+	}
+
+	for _, other := range streams {
+		if other.ID == stream.ID || other.IncomingPort != stream.IncomingPort {
+			continue
+		}
+		var shared []string
+		if other.TCPForwarding && stream.TCPForwarding {
+			shared = append(shared, "tcp")
+		}
+		if other.UDPForwarding && stream.UDPForwarding {
+			shared = append(shared, "udp")
+		}
+		if len(shared) == 0 {
+			continue
+		}
+
+		state := ""
+		if !other.Enabled {
+			state = ", disabled"
+		}
+		return fmt.Errorf("incoming port %d/%s is already used by stream %d (forwarding to %s:%d%s)",
+			stream.IncomingPort, strings.Join(shared, "+"), other.ID, other.ForwardingHost, other.ForwardingPort, state)
+	}
+	return nil
+}
+
+// validateStreamFlags checks the stream flags that were given
+func validateStreamFlags(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	for _, name := range []string{"incoming-port", "forward-port"} {
+		if flags.Changed(name) {
+			if port, _ := flags.GetInt(name); port < 1 || port > 65535 {
+				return fmt.Errorf("%s %d is out of range 1-65535", name, port)
+			}
+		}
+	}
+	if flags.Changed("forward-host") {
+		forwardHost, _ := flags.GetString("forward-host")
+		forwardHost = strings.TrimSpace(forwardHost)
+		if forwardHost == "" {
+			return fmt.Errorf("forward-host cannot be empty")
+		}
+		if strings.Contains(forwardHost, "://") || strings.Contains(forwardHost, "/") {
+			return fmt.Errorf("forward-host %q must be a host name or IP address", forwardHost)
+		}
+	}
+	return nil
+}
+
+// applyStreamFlags applies the stream flags that were given to the stream
+func applyStreamFlags(cmd *cobra.Command, stream *Stream) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("incoming-port") {
+		stream.IncomingPort, _ = flags.GetInt("incoming-port")
+	}
+	if flags.Changed("forward-host") {
+		forwardHost, _ := flags.GetString("forward-host")
+		stream.ForwardingHost = strings.TrimSpace(forwardHost)
+	}
+	if flags.Changed("forward-port") {
+		stream.ForwardingPort, _ = flags.GetInt("forward-port")
+	}
+	if flags.Changed("tcp") {
+		stream.TCPForwarding, _ = flags.GetBool("tcp")
+	}
+	if flags.Changed("udp") {
+		stream.UDPForwarding, _ = flags.GetBool("udp")
+	}
+
+	if !stream.TCPForwarding && !stream.UDPForwarding {
+		return fmt.Errorf("a stream needs to forward TCP, UDP or both")
+	}
+	return nil
+}
+
+// GetStream fetches a single stream by ID
+func (c *APIClient) GetStream(id int) (*Stream, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/nginx/streams/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("stream %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get stream, status: %d", resp.StatusCode)
+	}
+
+	var stream Stream
+	if err := decodeJSON(resp.Body, &stream); err != nil {
+		return nil, fmt.Errorf("failed to decode stream: %w", err)
+	}
+
+	return &stream, nil
+}
+
+// ListStreams lists all streams
+func (c *APIClient) ListStreams() ([]Stream, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/nginx/streams", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list streams, status: %d", resp.StatusCode)
+	}
+
+	var streams []Stream
+	if err := decodeJSON(resp.Body, &streams); err != nil {
+		return nil, fmt.Errorf("failed to decode streams: %w", err)
+	}
+
+	return streams, nil
+}
+
+// CreateStream creates a new stream
+func (c *APIClient) CreateStream(stream Stream) (*Stream, error) {
+	jsonData, err := json.Marshal(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stream: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", "/nginx/streams", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create stream, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var createdStream Stream
+	if err := decodeJSON(resp.Body, &createdStream); err != nil {
+		return nil, fmt.Errorf("failed to decode created stream: %w", err)
+	}
+
+	return &createdStream, nil
+}
+
+// UpdateStream updates an existing stream
+func (c *APIClient) UpdateStream(id int, stream Stream) (*Stream, error) {
+	jsonData, err := json.Marshal(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stream: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/nginx/streams/%d", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update stream, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var updatedStream Stream
+	if err := decodeJSON(resp.Body, &updatedStream); err != nil {
+		return nil, fmt.Errorf("failed to decode updated stream: %w", err)
+	}
+
+	return &updatedStream, nil
+}
+
+// DeleteStream deletes a stream by ID
+func (c *APIClient) DeleteStream(id int) error {
+	resp, err := c.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/nginx/streams/%d", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("stream %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete stream, status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SetStreamEnabled enables or disables a stream without changing its configuration
+func (c *APIClient) SetStreamEnabled(id int, enabled bool) error {
+	return c.setEnabled("streams", "stream", id, enabled)
+}
+
+// printStream prints the details of a stream
+func printStream(stream Stream) {
+	fmt.Fprintf(out, "ID: %d\n", stream.ID)
+	fmt.Fprintf(out, "Incoming Port: %d\n", stream.IncomingPort)
+	fmt.Fprintf(out, "Forward: %s:%d\n", stream.ForwardingHost, stream.ForwardingPort)
+	fmt.Fprintf(out, "Protocols: %s\n", streamProtocols(stream))
+	fmt.Fprintf(out, "Enabled: %t\n", stream.Enabled)
+}
+
+var streamCmd = &cobra.Command{
+	Use:   "stream",
+	Short: "Manage TCP/UDP streams",
+	Long: `Manage streams, which forward a TCP or UDP port of NPM to another host without
+any HTTP processing, for example for game servers or databases.`,
+}
+
+var streamListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all streams",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		streams, err := client.ListStreams()
+		if err != nil {
+			return fmt.Errorf("failed to list streams: %w", err)
+		}
+
+		if output == "json" {
+			return writeJSON(append([]Stream{}, streams...))
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tPORT\tFORWARD\tPROTOCOLS\tENABLED")
+		for _, stream := range streams {
+			fmt.Fprintf(w, "%d\t%d\t%s:%d\t%s\t%t\n", stream.ID, stream.IncomingPort, stream.ForwardingHost, stream.ForwardingPort,
+				streamProtocols(stream), stream.Enabled)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var streamGetCmd = &cobra.Command{
+	Use:   "get ID",
+	Short: "Show a stream",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		stream, err := client.GetStream(ids[0])
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(stream)
+		}
+		printStream(*stream)
+		return nil
+	},
+}
+
+var streamCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a stream",
+	Long: `Create a stream forwarding --incoming-port of NPM to --forward-host and
+--forward-port. Streams forward TCP unless --udp is given; give both --tcp and
+--udp to forward both.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		incomingPort, _ := cmd.Flags().GetInt("incoming-port")
+		forwardHost, _ := cmd.Flags().GetString("forward-host")
+		forwardPort, _ := cmd.Flags().GetInt("forward-port")
+		disabled, _ := cmd.Flags().GetBool("disabled")
+		if incomingPort == 0 || strings.TrimSpace(forwardHost) == "" || forwardPort == 0 {
+			return fmt.Errorf("incoming-port, forward-host, and forward-port are required")
+		}
+		if err := validateStreamFlags(cmd); err != nil {
+			return err
+		}
+
+		stream := Stream{
+			TCPForwarding: !cmd.Flags().Changed("udp"),
+			Enabled:       !disabled,
+		}
+		if err := applyStreamFlags(cmd, &stream); err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		streams, err := client.ListStreams()
+		if err != nil {
+			return fmt.Errorf("failed to list streams: %w", err)
+		}
+		if err := checkStreamPort(streams, stream); err != nil {
+			return err
+		}
+
+		createdStream, err := client.CreateStream(stream)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully created stream with ID: %d\n", createdStream.ID)
+		fmt.Fprintf(out, "Forward: %d -> %s:%d (%s)\n", createdStream.IncomingPort, createdStream.ForwardingHost, createdStream.ForwardingPort,
+			streamProtocols(*createdStream))
+		return nil
+	},
+}
+
+var streamUpdateCmd = &cobra.Command{
+	Use:   "update ID",
+	Short: "Update a stream",
+	Long: `Update a stream. Only the settings given as flags are changed, so --udp adds
+UDP forwarding and --tcp=false removes TCP forwarding.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		if err := validateStreamFlags(cmd); err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		stream, err := client.GetStream(ids[0])
+		if err != nil {
+			return err
+		}
+		if err := applyStreamFlags(cmd, stream); err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("incoming-port") || cmd.Flags().Changed("tcp") || cmd.Flags().Changed("udp") {
+			streams, err := client.ListStreams()
+			if err != nil {
+				return fmt.Errorf("failed to list streams: %w", err)
+			}
+			if err := checkStreamPort(streams, *stream); err != nil {
+				return err
+			}
+		}
+
+		updatedStream, err := client.UpdateStream(stream.ID, *stream)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully updated stream with ID: %d\n", updatedStream.ID)
+		fmt.Fprintf(out, "Forward: %d -> %s:%d (%s)\n", updatedStream.IncomingPort, updatedStream.ForwardingHost, updatedStream.ForwardingPort,
+			streamProtocols(*updatedStream))
+		return nil
+	},
+}
+
+var streamDeleteCmd = &cobra.Command{
+	Use:          "delete ID...",
+	Short:        "Delete streams",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		yes, _ := cmd.Flags().GetBool("yes")
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Delete %d streams?", len(ids))) {
+			return fmt.Errorf("aborted")
+		}
+
+		var result BatchResult
+		for _, id := range ids {
+			err := client.DeleteStream(id)
+			result.Record(err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to delete stream %d: %v\n", id, err)
+				continue
+			}
+			fmt.Fprintf(out, "Deleted stream %d\n", id)
+		}
+
+		return result.Err()
+	},
+}
+
+var streamEnableCmd = &cobra.Command{
+	Use:          "enable ID...",
+	Short:        "Enable streams",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleHosts("stream", (*APIClient).SetStreamEnabled, true),
+}
+
+var streamDisableCmd = &cobra.Command{
+	Use:          "disable ID...",
+	Short:        "Disable streams",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         toggleHosts("stream", (*APIClient).SetStreamEnabled, false),
+}
+
+// addStreamFlags registers the flags shared by stream create and update
+func addStreamFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("incoming-port", 0, "Port NPM listens on")
+	cmd.Flags().String("forward-host", "", "Host name or IP address to forward to")
+	cmd.Flags().Int("forward-port", 0, "Port to forward to")
+	cmd.Flags().Bool("tcp", false, "Forward TCP")
+	cmd.Flags().Bool("udp", false, "Forward UDP")
+}
+
+func init() {
+	addStreamFlags(streamCreateCmd)
+	streamCreateCmd.Flags().Bool("disabled", false, "Create the stream disabled")
+	addStreamFlags(streamUpdateCmd)
+
+	streamDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	streamCmd.AddCommand(streamListCmd)
+	streamCmd.AddCommand(streamGetCmd)
+	streamCmd.AddCommand(streamCreateCmd)
+	streamCmd.AddCommand(streamUpdateCmd)
+	streamCmd.AddCommand(streamDeleteCmd)
+	streamCmd.AddCommand(streamEnableCmd)
+	streamCmd.AddCommand(streamDisableCmd)
+	rootCmd.AddCommand(streamCmd)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// forwardTarget is the upstream a proxy host forwards to
+type forwardTarget struct {
+	Scheme string `json:"scheme"`
+	Host   string `json:"host"`
+	Port   int    `json:"port"`
+}
+
+func (t forwardTarget) String() string {
+	return fmt.Sprintf("%s://%s:%d", t.Scheme, t.Host, t.Port)
+}
+
+// hostTarget returns the forward target of a proxy host
+func hostTarget(host ProxyHost) forwardTarget {
+	return forwardTarget{host.ForwardScheme, host.ForwardHost, host.ForwardPort}
+}
+
+// switchEntry records one switch-target run so it can be rolled back
+type switchEntry struct {
+	Time     time.Time     `json:"time"`
+	APIURL   string        `json:"api_url"`
+	HostID   int           `json:"host_id"`
+	Previous forwardTarget `json:"previous"`
+	Current  forwardTarget `json:"current"`
+}
+
+// switchJournalFile is the state file recording switch-target runs
+const switchJournalFile = "switch-journal.json"
+
+// readSwitchJournal reads all journal entries, oldest first
+func readSwitchJournal() ([]switchEntry, error) {
+	var entries []switchEntry
+	err := readStateFile(switchJournalFile, &entries)
+	return entries, err
+}
+
+// writeSwitchJournal replaces the journal with the given entries
+func writeSwitchJournal(entries []switchEntry) error {
+	return writeStateFile(switchJournalFile, entries)
+}
+
+// lastSwitch returns the index of the newest journal entry of a host, or -1
+func lastSwitch(entries []switchEntry, apiURL string, hostID int) int {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].APIURL == apiURL && entries[i].HostID == hostID {
+			return i
+		}
+	}
+	return -1
+}
+
+// setForwardTarget points a proxy host at a new upstream, leaving all other settings alone
+func setForwardTarget(client *APIClient, host ProxyHost, target forwardTarget) (*ProxyHost, error) {
+	host.ForwardScheme = target.Scheme
+	host.ForwardHost = target.Host
+	host.ForwardPort = target.Port
+
+	updatedHost, err := client.UpdateProxyHost(host.ID, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update proxy host: %w", err)
+	}
+	return updatedHost, nil
+}
+
+var switchTargetCmd = &cobra.Command{
+	Use:   "switch-target",
+	Short: "Switch the upstream of a proxy host, e.g. for blue/green deployments",
+	Long: `Point a proxy host at a new forward host and port, leaving all its other
+settings untouched, and print the previous target.
+
+Every switch is recorded in a journal next to the config file. --rollback
+switches the host back to the target it had before the last switch and removes
+that entry, so repeated rollbacks walk further back in time.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		id, _ := cmd.Flags().GetInt("id")
+		domain, _ := cmd.Flags().GetString("domain")
+		forwardHost, _ := cmd.Flags().GetString("forward-host")
+		forwardPort, _ := cmd.Flags().GetInt("forward-port")
+		rollback, _ := cmd.Flags().GetBool("rollback")
+		force, _ := cmd.Flags().GetBool("force")
+		if id == 0 && domain == "" {
+			return fmt.Errorf("id or domain is required")
+		}
+		if rollback && (forwardHost != "" || forwardPort != 0) {
+			return fmt.Errorf("--rollback cannot be combined with --forward-host or --forward-port")
+		}
+		if !rollback && (forwardHost == "" || forwardPort == 0) {
+			return fmt.Errorf("forward-host and forward-port are required")
+		}
+
+		entries, err := readSwitchJournal()
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		if domain != "" {
+			hosts, err := client.ListProxyHosts()
+			if err != nil {
+				return fmt.Errorf("failed to list proxy hosts: %w", err)
+			}
+			selected, err := selectProxyHostByDomain(hosts, domain, false, id)
+			if err != nil {
+				return err
+			}
+			id = selected.ID
+		}
+
+		host, err := client.GetProxyHost(id)
+		if err != nil {
+			return err
+		}
+		previous := hostTarget(*host)
+
+		if rollback {
+			i := lastSwitch(entries, apiURL, host.ID)
+			if i < 0 {
+				return fmt.Errorf("no switch of proxy host %d recorded, nothing to roll back", host.ID)
+			}
+			entry := entries[i]
+
+			// Someone else may have changed the target since, don't silently undo that
+			if previous != entry.Current && !force {
+				return fmt.Errorf("proxy host %d forwards to %s, not %s as set by the last switch; use --force to roll back anyway",
+					host.ID, previous, entry.Current)
+			}
+
+			if _, err := setForwardTarget(client, *host, entry.Previous); err != nil {
+				return err
+			}
+			if err := writeSwitchJournal(append(entries[:i], entries[i+1:]...)); err != nil {
+				return fmt.Errorf("proxy host %d was rolled back, but %w", host.ID, err)
+			}
+
+			fmt.Fprintf(out, "Rolled back proxy host %d %v\n", host.ID, host.DomainNames)
+			fmt.Fprintf(out, "Previous target: %s\n", previous)
+			fmt.Fprintf(out, "Current target: %s\n", entry.Previous)
+			return nil
+		}
+
+		target := forwardTarget{Scheme: previous.Scheme, Host: forwardHost, Port: forwardPort}
+		if cmd.Flags().Changed("forward-scheme") {
+			target.Scheme, _ = cmd.Flags().GetString("forward-scheme")
+		}
+		if target == previous {
+			fmt.Fprintf(out, "Proxy host %d already forwards to %s\n", host.ID, target)
+			return nil
+		}
+
+		candidate := *host
+		candidate.ForwardHost, candidate.ForwardPort = target.Host, target.Port
+		if err := applyForwardLoopCheck(cmd, candidate); err != nil {
+			return err
+		}
+
+		if _, err := setForwardTarget(client, *host, target); err != nil {
+			return err
+		}
+
+		entries = append(entries, switchEntry{
+			Time:     time.Now().UTC(),
+			APIURL:   apiURL,
+			HostID:   host.ID,
+			Previous: previous,
+			Current:  target,
+		})
+		if err := writeSwitchJournal(entries); err != nil {
+			return fmt.Errorf("proxy host %d was switched from %s, but %w", host.ID, previous, err)
+		}
+
+		fmt.Fprintf(out, "Switched proxy host %d %v\n", host.ID, host.DomainNames)
+		fmt.Fprintf(out, "Previous target: %s\n", previous)
+		fmt.Fprintf(out, "Current target: %s\n", target)
+		return nil
+	},
+}
+
+func init() {
+	switchTargetCmd.Flags().Int("id", 0, "ID of the proxy host")
+	switchTargetCmd.Flags().String("domain", "", "Domain of the proxy host")
+	switchTargetCmd.Flags().String("forward-host", "", "New forward host")
+	switchTargetCmd.Flags().Int("forward-port", 0, "New forward port")
+	switchTargetCmd.Flags().String("forward-scheme", "", "New forward scheme (default: keep the current one)")
+	switchTargetCmd.Flags().Bool("rollback", false, "Switch back to the target before the last recorded switch")
+	switchTargetCmd.Flags().Bool("force", false, "Skip safety checks, like the forward loop check and the rollback target check")
+
+	rootCmd.AddCommand(switchTargetCmd)
+}
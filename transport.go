@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	preferIPv4 bool
+	preferIPv6 bool
+
+	tlsMinVersionName        = "1.2"
+	tlsMinVersion     uint16 = tls.VersionTLS12
+)
+
+// tlsVersions maps the accepted --tls-min-version values to their TLS versions
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion parses a --tls-min-version value
+func parseTLSVersion(name string) (uint16, error) {
+	version, ok := tlsVersions[name]
+	if !ok {
+		return 0, fmt.Errorf("invalid TLS version %q, expected 1.2 or 1.3", name)
+	}
+	return version, nil
+}
+
+// explainTLSError adds a hint to handshake failures caused by the TLS version floor
+func explainTLSError(err error) error {
+	if err != nil && strings.Contains(err.Error(), "protocol version") {
+		return fmt.Errorf("%w (the server does not support TLS %s or newer, required by --tls-min-version)", err, tlsMinVersionName)
+	}
+	return err
+}
+
+// dialNetwork returns the network used to dial the API host
+func dialNetwork() string {
+	switch {
+	case preferIPv4:
+		return "tcp4"
+	case preferIPv6:
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
+// newTransport builds the HTTP transport for API requests from the global flags
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tlsMinVersion}
+
+	// Pin the address family for dual-stack hosts where one family is broken
+	if network := dialNetwork(); network != "tcp" {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+		transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+
+	return transport
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// proxyHostFlagsChanged reports whether any flag that modifies a proxy host was given
+func proxyHostFlagsChanged(cmd *cobra.Command) bool {
+	for name := range flagFields {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return cmd.Flags().Changed("extra-json")
+}
+
+// applyProxyHostFlags applies the forward, SSL, option, location, advanced
+// config and Host header flags that were given on the command line to the host. It reports
+// whether the Host header was set.
+func applyProxyHostFlags(cmd *cobra.Command, host *ProxyHost) (bool, error) {
+	flags := cmd.Flags()
+
+	if flags.Changed("forward-host") {
+		host.ForwardHost, _ = flags.GetString("forward-host")
+	}
+	if flags.Changed("forward-port") {
+		host.ForwardPort, _ = flags.GetInt("forward-port")
+	}
+	if flags.Changed("forward-scheme") {
+		host.ForwardScheme, _ = flags.GetString("forward-scheme")
+	}
+
+	if err := applySSLFlags(cmd, host); err != nil {
+		return false, err
+	}
+	if err := applyHostOptionFlags(cmd, host); err != nil {
+		return false, err
+	}
+	applyAccessListIDFlag(cmd, host)
+	if err := applyLocationFlags(cmd, host); err != nil {
+		return false, err
+	}
+	// The Host header flags edit the advanced config, so they go after the file
+	if err := applyAdvancedConfigFile(cmd, host); err != nil {
+		return false, err
+	}
+	return applyHostHeaderFlags(cmd, host), nil
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update an existing proxy host",
+	Long: `Update an existing proxy host in place, keeping its ID.
+
+The host is fetched, only the settings given as flags are changed, and the
+result is sent back. --domain replaces all domain names of the host, use the
+domain command to add or remove single domains.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		id, _ := cmd.Flags().GetInt("id")
+		if id == 0 {
+			return fmt.Errorf("id is required")
+		}
+		if !proxyHostFlagsChanged(cmd) {
+			return fmt.Errorf("nothing to update, give at least one setting to change")
+		}
+
+		domainValues, _ := cmd.Flags().GetStringSlice("domain")
+		domains, err := parseDomainList(domainValues)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("domain") && len(domains) == 0 {
+			return fmt.Errorf("a proxy host needs at least one domain")
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		host, err := client.GetProxyHost(id)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("domain") {
+			host.DomainNames = domains
+		}
+
+		hostHeaderChanged, err := applyProxyHostFlags(cmd, host)
+		if err != nil {
+			return err
+		}
+		if err := resolveReferenceFlags(cmd, client, host); err != nil {
+			return err
+		}
+		if err := applyExtraJSON(cmd, host); err != nil {
+			return err
+		}
+
+		waitForOnline, _ := flags.GetBool("wait-for-online")
+		if waitForOnline && !host.Enabled {
+			return fmt.Errorf("proxy host %d is disabled and never comes online, --wait-for-online cannot be used", id)
+		}
+
+		// Only a changed forward target can introduce a loop
+		if flags.Changed("forward-host") || flags.Changed("forward-port") {
+			if err := applyForwardLoopCheck(cmd, *host); err != nil {
+				return err
+			}
+		}
+
+		updatedHost, err := client.UpdateProxyHost(id, *host)
+		if err != nil {
+			return fmt.Errorf("failed to update proxy host: %w", err)
+		}
+
+		fmt.Fprintf(out, "Successfully updated proxy host with ID: %d\n", updatedHost.ID)
+		fmt.Fprintf(out, "Domain: %v\n", updatedHost.DomainNames)
+		fmt.Fprintf(out, "Forward: %s://%s:%d\n", updatedHost.ForwardScheme, updatedHost.ForwardHost, updatedHost.ForwardPort)
+		fmt.Fprintf(out, "Enabled: %t\n", updatedHost.Enabled)
+		if hostHeaderChanged {
+			fmt.Fprintf(out, "Host Header: %s\n", hostHeaderSetting(updatedHost.AdvancedConfig))
+		}
+		fmt.Fprintf(out, "SSL: %s\n", sslStatus(*updatedHost))
+
+		if waitForOnline {
+			waitTimeout, _ := flags.GetDuration("wait-timeout")
+			if _, err := client.WaitForOnline(updatedHost.ID, waitTimeout); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "Proxy host is online")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	updateCmd.Flags().Int("id", 0, "ID of the proxy host to update")
+	updateCmd.Flags().StringSlice("domain", nil, "Replace the domain names of the proxy host (repeatable or comma separated)")
+	updateCmd.Flags().String("forward-host", "", "Forward host")
+	updateCmd.Flags().Int("forward-port", 0, "Forward port")
+	updateCmd.Flags().String("forward-scheme", "", "Forward scheme (http or https)")
+	addSSLFlags(updateCmd)
+	addHostOptionFlags(updateCmd)
+	addAccessListFlags(updateCmd)
+	addLocationFlags(updateCmd)
+	addAdvancedConfigFlag(updateCmd)
+	addHostHeaderFlags(updateCmd)
+	addExtraJSONFlag(updateCmd)
+	updateCmd.Flags().Bool("force", false, "Update the proxy host even if safety checks fail")
+	updateCmd.Flags().Bool("wait-for-online", false, "Wait until nginx reports the proxy host online")
+	updateCmd.Flags().Duration("wait-timeout", 60*time.Second, "Maximum time to wait with --wait-for-online")
+
+	rootCmd.AddCommand(updateCmd)
+}
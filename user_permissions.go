@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// UserPermissions is what a regular user may see and change in NPM. Admins
+// always have full access.
+type UserPermissions struct {
+	Visibility       string `json:"visibility"`
+	ProxyHosts       string `json:"proxy_hosts"`
+	RedirectionHosts string `json:"redirection_hosts"`
+	DeadHosts        string `json:"dead_hosts"`
+	Streams          string `json:"streams"`
+	AccessLists      string `json:"access_lists"`
+	Certificates     string `json:"certificates"`
+}
+
+// permissionItems are the item types of the permission matrix with the flag
+// setting each, in the order of the NPM UI
+var permissionItems = []struct {
+	Flag  string
+	Field func(p *UserPermissions) *string
+}{
+	{"proxy-hosts", func(p *UserPermissions) *string { return &p.ProxyHosts }},
+	{"redirection-hosts", func(p *UserPermissions) *string { return &p.RedirectionHosts }},
+	{"dead-hosts", func(p *UserPermissions) *string { return &p.DeadHosts }},
+	{"streams", func(p *UserPermissions) *string { return &p.Streams }},
+	{"access-lists", func(p *UserPermissions) *string { return &p.AccessLists }},
+	{"certificates", func(p *UserPermissions) *string { return &p.Certificates }},
+}
+
+// GetUserPermissions fetches the permissions of a user
+func (c *APIClient) GetUserPermissions(id int) (*UserPermissions, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/users/%d?expand=permissions", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("user %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get user permissions, status: %d", resp.StatusCode)
+	}
+
+	var user struct {
+		Permissions *UserPermissions `json:"permissions"`
+	}
+	if err := decodeJSON(resp.Body, &user); err != nil {
+		return nil, fmt.Errorf("failed to decode user permissions: %w", err)
+	}
+	if user.Permissions == nil {
+		return nil, fmt.Errorf("NPM returned no permissions for user %d", id)
+	}
+
+	return user.Permissions, nil
+}
+
+// SetUserPermissions replaces the permissions of a user
+func (c *APIClient) SetUserPermissions(id int, permissions UserPermissions) error {
+	jsonData, err := json.Marshal(permissions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user permissions: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/users/%d/permissions", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to set user permissions, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// validatePermissionFlags checks the permission flags that were given
+func validatePermissionFlags(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	if visibility, _ := flags.GetString("visibility"); flags.Changed("visibility") && visibility != "user" && visibility != "all" {
+		return fmt.Errorf("invalid visibility %q, expected user or all", visibility)
+	}
+	for _, name := range []string{"all", "proxy-hosts", "redirection-hosts", "dead-hosts", "streams", "access-lists", "certificates"} {
+		value, _ := flags.GetString(name)
+		if flags.Changed(name) && value != "hidden" && value != "view" && value != "manage" {
+			return fmt.Errorf("invalid %s permission %q, expected hidden, view or manage", name, value)
+		}
+	}
+	return nil
+}
+
+// applyPermissionFlags applies the permission flags that were given. --all
+// comes first, so single item types can be set apart from it.
+func applyPermissionFlags(cmd *cobra.Command, permissions *UserPermissions) {
+	flags := cmd.Flags()
+
+	if flags.Changed("visibility") {
+		permissions.Visibility, _ = flags.GetString("visibility")
+	}
+	if flags.Changed("all") {
+		all, _ := flags.GetString("all")
+		for _, item := range permissionItems {
+			*item.Field(permissions) = all
+		}
+	}
+	for _, item := range permissionItems {
+		if flags.Changed(item.Flag) {
+			*item.Field(permissions), _ = flags.GetString(item.Flag)
+		}
+	}
+}
+
+// printPermissions prints the permission matrix of a user
+func printPermissions(user User, permissions UserPermissions) {
+	fmt.Fprintf(out, "User %d (%s)\n", user.ID, user.Email)
+	if user.isAdmin() {
+		fmt.Fprintln(out, "Admin, the permissions below don't apply")
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ITEM\tPERMISSION")
+	fmt.Fprintf(w, "visibility\t%s\n", permissions.Visibility)
+	for _, item := range permissionItems {
+		fmt.Fprintf(w, "%s\t%s\n", item.Flag, *item.Field(&permissions))
+	}
+	w.Flush()
+}
+
+var userPermissionsCmd = &cobra.Command{
+	Use:   "permissions USER",
+	Short: "Show or change the permissions of a user",
+	Long: `Show the permissions of a user, given by ID or email address, or change them
+with flags. Each item type is hidden, view or manage; --all sets all of them at
+once and the flags of single item types override it. --visibility user limits
+the user to the items they created, all shows the items of every user.
+
+Only the permissions given as flags are changed. Admins always have full
+access, their permissions only apply once the admin role is taken away.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		if err := validatePermissionFlags(cmd); err != nil {
+			return err
+		}
+		changed := cmd.Flags().Changed("visibility") || cmd.Flags().Changed("all")
+		for _, item := range permissionItems {
+			changed = changed || cmd.Flags().Changed(item.Flag)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		user, err := resolveUser(client, args[0])
+		if err != nil {
+			return err
+		}
+		permissions, err := client.GetUserPermissions(user.ID)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			if user.isAdmin() {
+				fmt.Fprintf(os.Stderr, "Warning: user %d is an admin, the permissions only apply without the admin role\n", user.ID)
+			}
+
+			applyPermissionFlags(cmd, permissions)
+			if err := client.SetUserPermissions(user.ID, *permissions); err != nil {
+				return err
+			}
+		}
+
+		if output == "json" {
+			return writeJSON(permissions)
+		}
+		if changed {
+			fmt.Fprintf(out, "Successfully updated permissions of user %d\n", user.ID)
+		}
+		printPermissions(*user, *permissions)
+		return nil
+	},
+}
+
+func init() {
+	userPermissionsCmd.Flags().String("visibility", "", "Items the user sees: user (own items) or all")
+	userPermissionsCmd.Flags().String("all", "", "Permission for all item types: hidden, view or manage")
+	for _, item := range permissionItems {
+		userPermissionsCmd.Flags().String(item.Flag, "", fmt.Sprintf("Permission for %s: hidden, view or manage", item.Flag))
+	}
+
+	userCmd.AddCommand(userPermissionsCmd)
+}
@@ -0,0 +1,671 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// User represents a Nginx Proxy Manager user
+type User struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Nickname   string   `json:"nickname"`
+	Email      string   `json:"email"`
+	Roles      []string `json:"roles"`
+	IsDisabled bool     `json:"is_disabled"`
+	CreatedOn  string   `json:"created_on,omitempty"`
+	ModifiedOn string   `json:"modified_on,omitempty"`
+}
+
+// userRequest is the body of creating or updating a user. NPM rejects the
+// read-only fields of User.
+type userRequest struct {
+	Name       string    `json:"name"`
+	Nickname   string    `json:"nickname"`
+	Email      string    `json:"email"`
+	Roles      []string  `json:"roles"`
+	IsDisabled bool      `json:"is_disabled"`
+	Auth       *userAuth `json:"auth,omitempty"`
+}
+
+// userAuth sets the password of a new user
+type userAuth struct {
+	Type   string `json:"type"`
+	Secret string `json:"secret"`
+}
+
+// isAdmin reports whether the user has the admin role
+func (u User) isAdmin() bool {
+	for _, role := range u.Roles {
+		if role == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRoles checks roles given with --roles, NPM only knows admin
+func validateRoles(roles []string) error {
+	for _, role := range roles {
+		if role != "admin" {
+			return fmt.Errorf("invalid role %q, the only role is admin", role)
+		}
+	}
+	return nil
+}
+
+// SetPasswordRequest represents the request structure for changing a password
+type SetPasswordRequest struct {
+	Type    string `json:"type"`
+	Current string `json:"current,omitempty"`
+	Secret  string `json:"secret"`
+}
+
+// GetCurrentUser fetches the user the client is authenticated as
+func (c *APIClient) GetCurrentUser() (*User, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/users/me", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get current user, status: %d", resp.StatusCode)
+	}
+
+	var user User
+	if err := decodeJSON(resp.Body, &user); err != nil {
+		return nil, fmt.Errorf("failed to decode user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// ListUsers lists all users
+func (c *APIClient) ListUsers() ([]User, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", "/users", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list users, status: %d", resp.StatusCode)
+	}
+
+	var users []User
+	if err := decodeJSON(resp.Body, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUser fetches a single user by ID
+func (c *APIClient) GetUser(id int) (*User, error) {
+	resp, err := c.makeAuthenticatedRequest("GET", fmt.Sprintf("/users/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("user %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get user, status: %d", resp.StatusCode)
+	}
+
+	var user User
+	if err := decodeJSON(resp.Body, &user); err != nil {
+		return nil, fmt.Errorf("failed to decode user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// CreateUser creates a new user with the given password
+func (c *APIClient) CreateUser(user User, secret string) (*User, error) {
+	jsonData, err := json.Marshal(userRequest{
+		Name:       user.Name,
+		Nickname:   user.Nickname,
+		Email:      user.Email,
+		Roles:      user.Roles,
+		IsDisabled: user.IsDisabled,
+		Auth:       &userAuth{Type: "password", Secret: secret},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("POST", "/users", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create user, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var createdUser User
+	if err := decodeJSON(resp.Body, &createdUser); err != nil {
+		return nil, fmt.Errorf("failed to decode created user: %w", err)
+	}
+
+	return &createdUser, nil
+}
+
+// UpdateUser updates the name, email, roles and disabled state of a user
+func (c *APIClient) UpdateUser(id int, user User) (*User, error) {
+	jsonData, err := json.Marshal(userRequest{
+		Name:       user.Name,
+		Nickname:   user.Nickname,
+		Email:      user.Email,
+		Roles:      user.Roles,
+		IsDisabled: user.IsDisabled,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/users/%d", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to update user, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var updatedUser User
+	if err := decodeJSON(resp.Body, &updatedUser); err != nil {
+		return nil, fmt.Errorf("failed to decode updated user: %w", err)
+	}
+
+	return &updatedUser, nil
+}
+
+// DeleteUser deletes a user by ID
+func (c *APIClient) DeleteUser(id int) error {
+	resp, err := c.makeAuthenticatedRequest("DELETE", fmt.Sprintf("/users/%d", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("user %d not found", id)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete user, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// resolveUser returns the user given by ID or email address
+func resolveUser(client *APIClient, value string) (*User, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return client.GetUser(id)
+	}
+
+	users, err := client.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.Email, value) {
+			return &user, nil
+		}
+	}
+	return nil, fmt.Errorf("no user with email %s", value)
+}
+
+// SetUserPassword changes the password of a user. The current password is
+// only required when users change their own password.
+func (c *APIClient) SetUserPassword(id int, current, secret string) error {
+	jsonData, err := json.Marshal(SetPasswordRequest{
+		Type:    "password",
+		Current: current,
+		Secret:  secret,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal password request: %w", err)
+	}
+
+	resp, err := c.makeAuthenticatedRequest("PUT", fmt.Sprintf("/users/%d/auth", id), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to set password, status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage users",
+}
+
+var userSetPasswordCmd = &cobra.Command{
+	Use:   "set-password",
+	Short: "Change the password of the current user or, as admin, another user",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID, _ := cmd.Flags().GetInt("user-id")
+		passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		me, err := client.GetCurrentUser()
+		if err != nil {
+			return err
+		}
+
+		self := userID == 0 || userID == me.ID
+		if self {
+			userID = me.ID
+		}
+
+		var current string
+		switch {
+		case self && passwordStdin:
+			// stdin holds the new password, so the current one has to be
+			// the password we authenticated with
+			if password == "" {
+				return fmt.Errorf("--password-stdin needs the current password from --password or NPM_PASSWORD to change your own password")
+			}
+			current = password
+		case self:
+			if current, err = readPassword("Current password: "); err != nil {
+				return err
+			}
+		}
+
+		var newPassword string
+		if passwordStdin {
+			if newPassword, err = readNewPassword(true); err != nil {
+				return err
+			}
+		} else {
+			if newPassword, err = readPassword("New password: "); err != nil {
+				return err
+			}
+			if newPassword == "" {
+				return fmt.Errorf("new password must not be empty")
+			}
+
+			confirmation, err := readPassword("Confirm new password: ")
+			if err != nil {
+				return err
+			}
+			if newPassword != confirmation {
+				return fmt.Errorf("passwords do not match")
+			}
+		}
+
+		if err := client.SetUserPassword(userID, current, newPassword); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully changed password for user %d\n", userID)
+
+		// Our own token was issued for the old password, get a fresh one.
+		// A pre-issued token stays in use as it was given.
+		if self && token == "" {
+			if err := client.Authenticate(username, newPassword); err != nil {
+				return fmt.Errorf("re-authentication with the new password failed: %w", err)
+			}
+			if err := updateStoredPassword(newPassword); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+// updateStoredPassword replaces the old password where later commands would
+// read it from. Credentials saved by login are updated, a profile in the config
+// file has to be edited by the user.
+func updateStoredPassword(newPassword string) error {
+	switch passwordSource {
+	case "keyring", "file":
+		creds := storedCredentials{Username: username, Password: newPassword}
+		if err := saveCredentials(passwordSource, apiURL, creds); err != nil {
+			return fmt.Errorf("password changed, but updating the stored credentials failed, run login again: %w", err)
+		}
+		fmt.Fprintf(out, "Updated the stored credentials in: %s\n", passwordSource)
+	case "profile":
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: profile %s in %s still has the old password, update it there or later commands fail to authenticate\n", profileName, path)
+	}
+	return nil
+}
+
+// printUser prints the details of a user
+func printUser(user User) {
+	fmt.Fprintf(out, "ID: %d\n", user.ID)
+	fmt.Fprintf(out, "Name: %s\n", user.Name)
+	fmt.Fprintf(out, "Nickname: %s\n", user.Nickname)
+	fmt.Fprintf(out, "Email: %s\n", user.Email)
+	fmt.Fprintf(out, "Roles: %s\n", strings.Join(user.Roles, ", "))
+	fmt.Fprintf(out, "Disabled: %t\n", user.IsDisabled)
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all users",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		users, err := client.ListUsers()
+		if err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+
+		if output == "json" {
+			return writeJSON(append([]User{}, users...))
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tEMAIL\tROLES\tDISABLED")
+		for _, user := range users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\n", user.ID, user.Name, user.Email, strings.Join(user.Roles, ","), user.IsDisabled)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var userGetCmd = &cobra.Command{
+	Use:   "get USER",
+	Short: "Show a user",
+	Long:  `Show a user, given by ID or email address.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		user, err := resolveUser(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return writeJSON(user)
+		}
+		printUser(*user)
+		return nil
+	},
+}
+
+var userCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a user",
+	Long: `Create a user. The password is asked for twice, or read from the first line
+of stdin with --password-stdin for scripts. Without --roles admin the user is
+a regular user, whose permissions are set in NPM.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate required parameters before authentication
+		name, _ := cmd.Flags().GetString("name")
+		nickname, _ := cmd.Flags().GetString("nickname")
+		email, _ := cmd.Flags().GetString("email")
+		roles, _ := cmd.Flags().GetStringSlice("roles")
+		disabled, _ := cmd.Flags().GetBool("disabled")
+		passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+
+		name, email = strings.TrimSpace(name), strings.TrimSpace(email)
+		if name == "" || email == "" {
+			return fmt.Errorf("name and email are required")
+		}
+		if !strings.Contains(email, "@") {
+			return fmt.Errorf("invalid email %q", email)
+		}
+		if err := validateRoles(roles); err != nil {
+			return err
+		}
+		// NPM requires a nickname, the UI suggests the first name
+		if nickname == "" {
+			nickname = strings.Fields(name)[0]
+		}
+
+		secret, err := readNewPassword(passwordStdin)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		user, err := client.CreateUser(User{
+			Name:       name,
+			Nickname:   nickname,
+			Email:      email,
+			Roles:      append([]string{}, roles...),
+			IsDisabled: disabled,
+		}, secret)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully created user with ID: %d\n", user.ID)
+		fmt.Fprintf(out, "Email: %s\n", user.Email)
+		fmt.Fprintf(out, "Roles: %s\n", strings.Join(user.Roles, ", "))
+		return nil
+	},
+}
+
+var userUpdateCmd = &cobra.Command{
+	Use:   "update USER",
+	Short: "Update a user",
+	Long: `Update a user, given by ID or email address. Only the settings given as flags
+are changed; --roles "" makes an admin a regular user.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters before authentication
+		flags := cmd.Flags()
+		roles, _ := flags.GetStringSlice("roles")
+		if err := validateRoles(roles); err != nil {
+			return err
+		}
+		if email, _ := flags.GetString("email"); flags.Changed("email") && !strings.Contains(email, "@") {
+			return fmt.Errorf("invalid email %q", email)
+		}
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		user, err := resolveUser(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if flags.Changed("name") {
+			user.Name, _ = flags.GetString("name")
+		}
+		if flags.Changed("nickname") {
+			user.Nickname, _ = flags.GetString("nickname")
+		}
+		if flags.Changed("email") {
+			user.Email, _ = flags.GetString("email")
+		}
+		if flags.Changed("roles") {
+			user.Roles = append([]string{}, roles...)
+		}
+
+		me, err := client.GetCurrentUser()
+		if err != nil {
+			return err
+		}
+		if user.ID == me.ID && me.isAdmin() && !user.isAdmin() {
+			return fmt.Errorf("refusing to remove the admin role from yourself")
+		}
+
+		updatedUser, err := client.UpdateUser(user.ID, *user)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully updated user with ID: %d\n", updatedUser.ID)
+		printUser(*updatedUser)
+		return nil
+	},
+}
+
+// setUserDisabled returns the RunE of user disable and enable
+func setUserDisabled(disabled bool) func(cmd *cobra.Command, args []string) error {
+	done := "Enabled"
+	if disabled {
+		done = "Disabled"
+	}
+
+	return func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		user, err := resolveUser(client, args[0])
+		if err != nil {
+			return err
+		}
+		if disabled {
+			me, err := client.GetCurrentUser()
+			if err != nil {
+				return err
+			}
+			if user.ID == me.ID {
+				return fmt.Errorf("refusing to disable yourself")
+			}
+		}
+
+		user.IsDisabled = disabled
+		if _, err := client.UpdateUser(user.ID, *user); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "%s user %d (%s)\n", done, user.ID, user.Email)
+		return nil
+	}
+}
+
+var userDisableCmd = &cobra.Command{
+	Use:   "disable USER",
+	Short: "Disable a user",
+	Long: `Disable a user, given by ID or email address. Disabled users can't log in but
+keep their hosts and settings.`,
+	Args: cobra.ExactArgs(1),
+	RunE: setUserDisabled(true),
+}
+
+var userEnableCmd = &cobra.Command{
+	Use:   "enable USER",
+	Short: "Enable a disabled user",
+	Args:  cobra.ExactArgs(1),
+	RunE:  setUserDisabled(false),
+}
+
+var userDeleteCmd = &cobra.Command{
+	Use:   "delete USER",
+	Short: "Delete a user",
+	Long: `Delete a user, given by ID or email address. Use disable instead to only
+block the login.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		client, err := newAuthenticatedClient()
+		if err != nil {
+			return err
+		}
+
+		user, err := resolveUser(client, args[0])
+		if err != nil {
+			return err
+		}
+		me, err := client.GetCurrentUser()
+		if err != nil {
+			return err
+		}
+		if user.ID == me.ID {
+			return fmt.Errorf("refusing to delete yourself")
+		}
+
+		if !yes && !confirm(fmt.Sprintf("Delete user %d (%s)?", user.ID, user.Email)) {
+			return fmt.Errorf("aborted")
+		}
+
+		if err := client.DeleteUser(user.ID); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Successfully deleted user with ID: %d\n", user.ID)
+		return nil
+	},
+}
+
+func init() {
+	userSetPasswordCmd.Flags().Int("user-id", 0, "ID of the user to change (admin only, defaults to the current user)")
+
+	userSetPasswordCmd.Flags().Bool("password-stdin", false, "Read the new password from the first line of stdin")
+
+	userCreateCmd.Flags().String("name", "", "Full name of the user")
+	userCreateCmd.Flags().String("nickname", "", "Nickname of the user (defaults to the first word of --name)")
+	userCreateCmd.Flags().String("email", "", "Email address, used to log in")
+	userCreateCmd.Flags().StringSlice("roles", nil, "Roles of the user, admin or none")
+	userCreateCmd.Flags().Bool("disabled", false, "Create the user disabled")
+	userCreateCmd.Flags().Bool("password-stdin", false, "Read the password from the first line of stdin")
+
+	userUpdateCmd.Flags().String("name", "", "Full name of the user")
+	userUpdateCmd.Flags().String("nickname", "", "Nickname of the user")
+	userUpdateCmd.Flags().String("email", "", "Email address, used to log in")
+	userUpdateCmd.Flags().StringSlice("roles", nil, "Roles of the user, admin or none")
+
+	userDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+
+	userCmd.AddCommand(userListCmd)
+	userCmd.AddCommand(userGetCmd)
+	userCmd.AddCommand(userCreateCmd)
+	userCmd.AddCommand(userUpdateCmd)
+	userCmd.AddCommand(userDisableCmd)
+	userCmd.AddCommand(userEnableCmd)
+	userCmd.AddCommand(userDeleteCmd)
+	userCmd.AddCommand(userSetPasswordCmd)
+	rootCmd.AddCommand(userCmd)
+}